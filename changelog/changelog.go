@@ -28,6 +28,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/akozlenkov/go-debian/control"
 	"github.com/akozlenkov/go-debian/version"
 )
 
@@ -138,6 +139,30 @@ func ParseOne(reader *bufio.Reader) (*ChangelogEntry, error) {
 	return &changeLog, nil
 }
 
+// An Author is the person responsible for a ChangelogEntry, as named in
+// the trailer line of the entry.
+type Author struct {
+	Name  string
+	Email string
+}
+
+// Parse the ChangedBy of the entry (the `Name <email>` part of the
+// trailer line) into an Author.
+func (c *ChangelogEntry) Author() (Author, error) {
+	name, email, err := control.ParseEmailAddress(c.ChangedBy)
+	if err != nil {
+		return Author{}, err
+	}
+	return Author{Name: name, Email: email}, nil
+}
+
+// Return the trailer line of the entry, in the canonical form of
+// ` -- Name <email>  Mon, 02 Jan 2006 15:04:05 -0700`. Note the two spaces
+// between the address and the date, which are required.
+func (c *ChangelogEntry) TrailerLine() string {
+	return fmt.Sprintf(" -- %s  %s", c.ChangedBy, c.When.Format(whenLayout))
+}
+
 func ParseFileOne(path string) (*ChangelogEntry, error) {
 	f, err := os.Open(path)
 	if err != nil {
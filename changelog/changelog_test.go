@@ -93,6 +93,26 @@ func TestChangelogEntry(t *testing.T) {
 	assert(t, changeLog.ChangedBy == "Santiago Vila <sanvila@debian.org>")
 }
 
+func TestChangelogAuthor(t *testing.T) {
+	changeLog, err := changelog.ParseOne(bufio.NewReader(strings.NewReader(changeLog)))
+	isok(t, err)
+
+	author, err := changeLog.Author()
+	isok(t, err)
+	assert(t, author == changelog.Author{Name: "Santiago Vila", Email: "sanvila@debian.org"})
+	assert(t, changeLog.TrailerLine() == " -- Santiago Vila <sanvila@debian.org>  Sun, 22 Mar 2015 11:56:00 +0100")
+
+	changeLog.ChangedBy = `"Vila, Santiago <the <hello> guy>" <sanvila@debian.org>`
+	author, err = changeLog.Author()
+	isok(t, err)
+	assert(t, author.Name == "Vila, Santiago <the <hello> guy>")
+	assert(t, author.Email == "sanvila@debian.org")
+
+	changeLog.ChangedBy = "Santiago Vila"
+	_, err = changeLog.Author()
+	notok(t, err)
+}
+
 func TestChangelogEntries(t *testing.T) {
 	changeLogs, err := changelog.Parse(strings.NewReader(changeLog))
 	isok(t, err)
@@ -0,0 +1,203 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control // import "github.com/akozlenkov/go-debian/control"
+
+import (
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+)
+
+// An Author is a person (or team) named by an address in a field such as
+// Maintainer or Uploaders.
+type Author struct {
+	Name  string
+	Email string
+}
+
+// Split an RFC 5322-ish address, as used in the Maintainer, Changed-By and
+// Uploaders fields, into the display name and email address. This accepts
+// the `Name <email>` form (where the name may contain commas, be quoted, or
+// be RFC 2047 encoded), `<email>` with no name, the obsolete
+// `email (Name)` form, and a bare `email`. See ParseRFC5322Address.
+func ParseEmailAddress(address string) (name, email string, err error) {
+	return ParseRFC5322Address(address)
+}
+
+// Split a single address into the display name and email address, parsing
+// it as an RFC 5322 address with net/mail first. Plenty of addresses found
+// in the wild aren't strictly valid (such as `J. Random <jr@example.com>`,
+// where the unquoted `.` isn't allowed), so if net/mail rejects the
+// address, it's parsed again more leniently, which also covers the bare
+// addresses without angle brackets used by some old packages.
+func ParseRFC5322Address(address string) (name, email string, err error) {
+	address = strings.TrimSpace(address)
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		return parsed.Name, parsed.Address, nil
+	}
+	return parseLenientAddress(address)
+}
+
+// Split an address into the display name and email address without
+// following RFC 5322 too closely, for addresses net/mail won't parse.
+func parseLenientAddress(address string) (name, email string, err error) {
+	if open := strings.LastIndex(address, "<"); open != -1 && strings.HasSuffix(address, ">") {
+		name = strings.TrimSpace(address[:open])
+		email = strings.TrimSpace(address[open+1 : len(address)-1])
+	} else if open := strings.Index(address, "("); open != -1 && strings.HasSuffix(address, ")") {
+		email = strings.TrimSpace(address[:open])
+		name = strings.TrimSpace(address[open+1 : len(address)-1])
+	} else {
+		email = address
+	}
+
+	if !strings.Contains(email, "@") || strings.ContainsAny(email, " \t<>()") {
+		return "", "", fmt.Errorf("Invalid email address: '%s'", address)
+	}
+
+	if len(name) >= 2 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+		name = unquoteString(name)
+	}
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(name); err == nil {
+		name = decoded
+	}
+
+	return name, email, nil
+}
+
+// The characters which can't appear in a display name without it being
+// quoted, the specials of RFC 5322.
+const addressSpecials = `()<>[]:;@\,."`
+
+// Format a name and email address as `Name <email>`, quoting the name if
+// it contains characters which would otherwise confuse parsing. This is the
+// inverse of ParseEmailAddress. Control characters (other than tab) can't
+// be written in the name or email at all, and are rejected with an error.
+func FormatEmailAddress(name, email string) (string, error) {
+	for _, r := range email {
+		if isControl(r) || r == '\t' {
+			return "", fmt.Errorf("Invalid character %U in email address", r)
+		}
+	}
+	if name == "" {
+		return email, nil
+	}
+	if strings.ContainsAny(name, addressSpecials) {
+		quoted, err := quoteString(name)
+		if err != nil {
+			return "", err
+		}
+		name = quoted
+	} else {
+		for _, r := range name {
+			if isControl(r) {
+				return "", fmt.Errorf("Invalid character %U in name", r)
+			}
+		}
+	}
+	return fmt.Sprintf("%s <%s>", name, email), nil
+}
+
+// Check to see if the rune is a control character, other than tab, which
+// can't be written in an address, even quoted.
+func isControl(r rune) bool {
+	return (r < 0x20 && r != '\t') || r == 0x7f
+}
+
+// Quote the string as an RFC 5322 quoted-string, escaping only `"` and `\`
+// with a backslash. Control characters can't be quoted, and are rejected
+// with an error.
+func quoteString(in string) (string, error) {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for _, r := range in {
+		switch {
+		case isControl(r):
+			return "", fmt.Errorf("Invalid character %U in quoted string", r)
+		case r == '"' || r == '\\':
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(r)
+	}
+	buf.WriteByte('"')
+	return buf.String(), nil
+}
+
+// Undo quoteString, removing the surrounding quotes and the backslash from
+// each quoted-pair of an RFC 5322 quoted-string.
+func unquoteString(in string) string {
+	in = in[1 : len(in)-1]
+	var buf strings.Builder
+	for i := 0; i < len(in); i++ {
+		if in[i] == '\\' && i+1 < len(in) {
+			i++
+		}
+		buf.WriteByte(in[i])
+	}
+	return buf.String()
+}
+
+// Split a comma separated list of addresses, such as the Uploaders field,
+// into each address. Commas inside of quotes, angle brackets or
+// parentheses don't separate addresses.
+func splitAddressList(list string) []string {
+	ret := []string{}
+	var quoted bool
+	var depth int
+	start := 0
+	for i, c := range list {
+		switch {
+		case c == '"' && (i == 0 || list[i-1] != '\\'):
+			quoted = !quoted
+		case quoted:
+		case c == '<' || c == '(':
+			depth++
+		case (c == '>' || c == ')') && depth > 0:
+			depth--
+		case c == ',' && depth == 0:
+			if el := strings.TrimSpace(list[start:i]); el != "" {
+				ret = append(ret, el)
+			}
+			start = i + 1
+		}
+	}
+	if el := strings.TrimSpace(list[start:]); el != "" {
+		ret = append(ret, el)
+	}
+	return ret
+}
+
+// Parse a comma separated list of addresses, such as the Uploaders field,
+// into a list of Authors.
+func parseAuthors(list string) ([]Author, error) {
+	ret := []Author{}
+	for _, address := range splitAddressList(list) {
+		name, email, err := ParseEmailAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, Author{Name: name, Email: email})
+	}
+	return ret, nil
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,116 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control_test
+
+import (
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+/*
+ *
+ */
+
+func TestParseEmailAddress(t *testing.T) {
+	for address, expected := range map[string][2]string{
+		"Paul Tagliamonte <paultag@debian.org>":      {"Paul Tagliamonte", "paultag@debian.org"},
+		"<paultag@debian.org>":                       {"", "paultag@debian.org"},
+		"paultag@debian.org":                         {"", "paultag@debian.org"},
+		"paultag@debian.org (Paul Tagliamonte)":      {"Paul Tagliamonte", "paultag@debian.org"},
+		`"Tagliamonte, Paul" <paultag@debian.org>`:   {"Tagliamonte, Paul", "paultag@debian.org"},
+		"=?UTF-8?Q?J=C3=B6rg?= <debian@jff.email>":   {"Jörg", "debian@jff.email"},
+		"Jörg Frings-Fürst <debian@jff.email>":       {"Jörg Frings-Fürst", "debian@jff.email"},
+		"  Paul Tagliamonte  <paultag@debian.org>  ": {"Paul Tagliamonte", "paultag@debian.org"},
+	} {
+		name, email, err := control.ParseEmailAddress(address)
+		isok(t, err)
+		assert(t, name == expected[0])
+		assert(t, email == expected[1])
+	}
+
+	for _, address := range []string{"", "Paul Tagliamonte", "Paul <paul tag@debian.org>"} {
+		_, _, err := control.ParseEmailAddress(address)
+		notok(t, err)
+	}
+}
+
+func TestParseRFC5322Address(t *testing.T) {
+	for address, expected := range map[string][2]string{
+		`"Lastname, Firstname" <first@example.com>`:     {"Lastname, Firstname", "first@example.com"},
+		`"J. \"Random\" Hacker" <jrh@example.com>`:      {`J. "Random" Hacker`, "jrh@example.com"},
+		"J. Random Hacker <jrh@example.com>":            {"J. Random Hacker", "jrh@example.com"},
+		"=?ISO-8859-1?Q?Andr=E9?= <andre@example.com>":  {"André", "andre@example.com"},
+		"Debian QA Group <packages@qa.debian.org>":      {"Debian QA Group", "packages@qa.debian.org"},
+		"packages@qa.debian.org":                        {"", "packages@qa.debian.org"},
+		"packages@qa.debian.org (Debian QA Group)":      {"Debian QA Group", "packages@qa.debian.org"},
+		"Santiago Vila <sanvila@debian.org> ":           {"Santiago Vila", "sanvila@debian.org"},
+		"Ubuntu Developers <ubuntu-devel@ubuntu.com>\n": {"Ubuntu Developers", "ubuntu-devel@ubuntu.com"},
+	} {
+		name, email, err := control.ParseRFC5322Address(address)
+		isok(t, err)
+		assert(t, name == expected[0])
+		assert(t, email == expected[1])
+	}
+
+	for _, address := range []string{"", "Debian QA Group", "<>", "QA <packages at qa.debian.org>"} {
+		_, _, err := control.ParseRFC5322Address(address)
+		notok(t, err)
+	}
+}
+
+func TestFormatEmailAddress(t *testing.T) {
+	for _, el := range [][2]string{
+		{"Paul Tagliamonte", "paultag@debian.org"},
+		{"Tagliamonte, Paul", "paultag@debian.org"},
+		{"Paul (paultag) Tagliamonte", "paultag@debian.org"},
+		{"", "paultag@debian.org"},
+		{`Paul "paultag" Tagliamonte`, "paultag@debian.org"},
+		{`Back\slash, Paul`, "paultag@debian.org"},
+		{"J. Random Hacker", "jrandom@example.com"},
+		{"Jörg Müller", "joerg@example.com"},
+	} {
+		address, err := control.FormatEmailAddress(el[0], el[1])
+		isok(t, err)
+		name, email, err := control.ParseEmailAddress(address)
+		isok(t, err)
+		assert(t, name == el[0])
+		assert(t, email == el[1])
+	}
+
+	address, err := control.FormatEmailAddress(`Paul "paultag" Tagliamonte`, "paultag@debian.org")
+	isok(t, err)
+	assert(t, address == `"Paul \"paultag\" Tagliamonte" <paultag@debian.org>`)
+	address, err = control.FormatEmailAddress("Jörg Müller", "joerg@example.com")
+	isok(t, err)
+	assert(t, address == "Jörg Müller <joerg@example.com>")
+
+	for _, el := range [][2]string{
+		{"Paul\nTagliamonte", "paultag@debian.org"},
+		{"Tagliamonte,\x00 Paul", "paultag@debian.org"},
+		{"Paul Tagliamonte", "paultag@debian.org\nX-Injected: yes"},
+	} {
+		_, err := control.FormatEmailAddress(el[0], el[1])
+		notok(t, err)
+	}
+}
+
+// vim: foldmethod=marker
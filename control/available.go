@@ -0,0 +1,78 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control // import "github.com/akozlenkov/go-debian/control"
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// The AvailableReader struct allows iteration over the BinaryIndex entries
+// of a dpkg `available` file (usually /var/lib/dpkg/available) without
+// loading the whole file into memory.
+//
+// The available file is maintained locally by dpkg, and is formatted like an
+// APT Packages index. Entries may carry additional dpkg-specific fields, such
+// as a Status field for packages which have been selected for removal; these
+// are kept in the Paragraph of each entry.
+type AvailableReader struct {
+	decoder *Decoder
+}
+
+// Create a new AvailableReader reading from the given `io.Reader`.
+func NewAvailableReader(reader io.Reader) (*AvailableReader, error) {
+	decoder, err := NewDecoder(reader, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AvailableReader{decoder: decoder}, nil
+}
+
+// Consume the io.Reader and return the next parsed BinaryIndex. Once all
+// entries have been read, io.EOF is returned.
+func (a *AvailableReader) Next() (*BinaryIndex, error) {
+	ret := BinaryIndex{}
+	if err := a.decoder.Decode(&ret); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// Given a path on the filesystem, parse the dpkg available file off the
+// disk and return the list of BinaryIndex entries it contains.
+func ParseAvailableFile(path string) ([]BinaryIndex, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseBinaryIndex(bufio.NewReader(f))
+}
+
+// vim: foldmethod=marker
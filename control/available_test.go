@@ -0,0 +1,87 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+// Available file {{{
+const availableFile = `Package: hello
+Priority: optional
+Section: devel
+Installed-Size: 280
+Maintainer: Santiago Vila <sanvila@debian.org>
+Architecture: amd64
+Version: 2.10-2
+Depends: libc6 (>= 2.14)
+Size: 56132
+Description: example package based on GNU hello
+
+Package: fbautostart
+Status: deinstall ok config-files
+Priority: optional
+Section: misc
+Maintainer: Paul Tagliamonte <paultag@ubuntu.com>
+Architecture: amd64
+Version: 2.718281828-1
+Description: XDG compliant autostarting app for Fluxbox
+`
+
+// }}}
+
+func TestAvailableReader(t *testing.T) {
+	reader, err := control.NewAvailableReader(strings.NewReader(availableFile))
+	isok(t, err)
+
+	hello, err := reader.Next()
+	isok(t, err)
+	assert(t, hello.Package == "hello")
+	assert(t, hello.Version.Version == "2.10")
+	assert(t, hello.InstalledSize == 280)
+
+	fbautostart, err := reader.Next()
+	isok(t, err)
+	assert(t, fbautostart.Package == "fbautostart")
+	assert(t, fbautostart.Values["Status"] == "deinstall ok config-files")
+
+	_, err = reader.Next()
+	assert(t, err == io.EOF)
+}
+
+func TestParseAvailableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "available")
+	isok(t, os.WriteFile(path, []byte(availableFile), 0644))
+
+	packages, err := control.ParseAvailableFile(path)
+	isok(t, err)
+	assert(t, len(packages) == 2)
+	assert(t, packages[0].Package == "hello")
+	assert(t, packages[1].Package == "fbautostart")
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,99 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control // import "github.com/akozlenkov/go-debian/control"
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/akozlenkov/go-debian/dependency"
+	"github.com/akozlenkov/go-debian/version"
+)
+
+// The BuildInfo struct is the encapsulation of a Debian .buildinfo file,
+// which records the environment a package was built in, so that the build
+// can be reproduced later. This struct contains an anonymous member of type
+// Paragraph, allowing you to use the standard .Values and .Order of the
+// Paragraph type.
+type BuildInfo struct {
+	Paragraph
+
+	Filename string
+
+	Format            string
+	Source            string
+	Binaries          []string          `control:"Binary" delim:" "`
+	Architectures     []dependency.Arch `control:"Architecture"`
+	Version           version.Version
+	BinaryOnlyChanges string           `control:"Binary-Only-Changes"`
+	ChecksumsMd5      []MD5FileHash    `control:"Checksums-Md5" delim:"\n" strip:"\n\r\t "`
+	ChecksumsSha1     []SHA1FileHash   `control:"Checksums-Sha1" delim:"\n" strip:"\n\r\t "`
+	ChecksumsSha256   []SHA256FileHash `control:"Checksums-Sha256" delim:"\n" strip:"\n\r\t "`
+
+	BuildOrigin        string          `control:"Build-Origin"`
+	BuildArchitecture  dependency.Arch `control:"Build-Architecture"`
+	BuildDate          string          `control:"Build-Date"`
+	BuildKernelVersion string          `control:"Build-Kernel-Version"`
+	BuildPath          string          `control:"Build-Path"`
+	BuildTaintedBy     []string        `control:"Build-Tainted-By" delim:"\n" strip:"\n\r\t "`
+
+	InstalledBuildDepends []dependency.VersionedPackage `control:"Installed-Build-Depends" delim:"," strip:"\n\r\t "`
+
+	// The environment variables set during the build, one `NAME="value"`
+	// assignment per entry, as they appear in the .buildinfo file.
+	Environment []string `delim:"\n" strip:"\n\r\t "`
+}
+
+// Given a path on the filesystem, Parse the file off the disk and return
+// a pointer to a brand new BuildInfo struct, unless error is set to a value
+// other than nil.
+func ParseBuildInfoFile(path string) (*BuildInfo, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ret, err := ParseBuildInfo(f)
+	if err != nil {
+		return nil, err
+	}
+	ret.Filename = path
+	return ret, nil
+}
+
+// Given an io.Reader, consume the Reader, and return a BuildInfo object
+// for use. If the .buildinfo is clearsigned, the signature is *not* checked.
+func ParseBuildInfo(reader io.Reader) (*BuildInfo, error) {
+	ret := BuildInfo{}
+	if err := Unmarshal(&ret, reader); err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// vim: foldmethod=marker
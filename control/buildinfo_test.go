@@ -0,0 +1,86 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+/*
+ *
+ */
+
+func TestBuildInfoParse(t *testing.T) {
+	// Test BuildInfo {{{
+	buildInfo, err := control.ParseBuildInfo(strings.NewReader(`Format: 1.0
+Source: hello
+Binary: hello hello-dbgsym
+Architecture: amd64
+Version: 2.10-3
+Checksums-Md5:
+ 5a6a0d5bd1ba5e426bb10d61050e9e06 53920 hello_2.10-3_amd64.deb
+Checksums-Sha256:
+ 2f8358e0c3d1c9a5bba3674c1e1ee3d405cf1df4dceb2ec1e7b81798fa47c552 53920 hello_2.10-3_amd64.deb
+Build-Origin: Debian
+Build-Architecture: amd64
+Build-Date: Sun, 13 Aug 2023 15:39:46 +0000
+Build-Path: /build/reproducible-path/hello-2.10
+Installed-Build-Depends:
+ autoconf (= 2.71-3),
+ libc6:amd64 (= 2.36-9),
+ perl-base (= 5.36.0-7+deb12u1)
+Environment:
+ DEB_BUILD_OPTIONS="parallel=4"
+ LANG="C.UTF-8"
+ SOURCE_DATE_EPOCH="1691940786"
+`))
+	// }}}
+	isok(t, err)
+
+	assert(t, buildInfo.Source == "hello")
+	assert(t, len(buildInfo.Binaries) == 2)
+	assert(t, buildInfo.Version.Revision == "3")
+	assert(t, buildInfo.BuildArchitecture.CPU == "amd64")
+	assert(t, buildInfo.BuildPath == "/build/reproducible-path/hello-2.10")
+	assert(t, len(buildInfo.ChecksumsMd5) == 1)
+	assert(t, buildInfo.ChecksumsSha256[0].Size == 53920)
+
+	assert(t, len(buildInfo.InstalledBuildDepends) == 3)
+	assert(t, buildInfo.InstalledBuildDepends[0].Name == "autoconf")
+	assert(t, buildInfo.InstalledBuildDepends[1].Arch.CPU == "amd64")
+	assert(t, buildInfo.InstalledBuildDepends[2].Version.Revision == "7+deb12u1")
+
+	assert(t, len(buildInfo.Environment) == 3)
+	assert(t, buildInfo.Environment[1] == `LANG="C.UTF-8"`)
+}
+
+func TestBuildInfoParseBadDepends(t *testing.T) {
+	_, err := control.ParseBuildInfo(strings.NewReader(`Format: 1.0
+Source: hello
+Installed-Build-Depends: autoconf (>= 2.71-3)
+`))
+	notok(t, err)
+}
+
+// vim: foldmethod=marker
@@ -22,10 +22,14 @@ package control // import "github.com/akozlenkov/go-debian/control"
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -63,6 +67,10 @@ func (c *FileListChangesFileHash) UnmarshalControl(data string) error {
 	return nil
 }
 
+func (c FileListChangesFileHash) MarshalControl() (string, error) {
+	return fmt.Sprintf("%s %d %s %s %s", c.Hash, c.Size, c.Component, c.Priority, c.Filename), nil
+}
+
 // }}}
 
 // The Changes struct is the default encapsulation of the Debian .changes
@@ -114,6 +122,113 @@ func ParseChangesFile(path string) (ret *Changes, err error) {
 	return ParseChanges(bufio.NewReader(f), path)
 }
 
+// Parse all of the .changes files in the given directory, in lexicographic
+// order by filename, such as the contents of an upload queue. A file which
+// fails to parse doesn't stop the rest being read; the Changes which did
+// parse are returned along with a MultiError holding each failure.
+func ReadChangesDir(dir string) ([]*Changes, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*Changes{}
+	errs := MultiError{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".changes") {
+			continue
+		}
+		changes, err := ParseChangesFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		ret = append(ret, changes)
+	}
+
+	if len(errs) != 0 {
+		return ret, errs
+	}
+	return ret, nil
+}
+
+// {{{ Incoming uploads
+
+// ErrMissingFile is returned by ParseIncoming for an upload which is
+// missing some of the files listed in its .changes.
+var ErrMissingFile = errors.New("file missing from upload")
+
+// An UploadSet is a .changes file in an incoming directory, along with the
+// files it lists, which are all present next to it as regular files.
+type UploadSet struct {
+	Changes *Changes
+
+	// The files listed in the .changes, keyed by name, with the absolute
+	// path of each on disk.
+	Files map[string]string
+}
+
+// Scan an incoming directory (such as the upload queue of an archive) for
+// .changes files, and match each of them up with the files it lists. Only
+// uploads with all their files present are returned. As with
+// ReadChangesDir, a failure doesn't stop the rest of the directory being
+// read; each .changes which failed to parse, or is missing files (which
+// wraps ErrMissingFile), is reported in a MultiError returned along with
+// the complete uploads.
+func ParseIncoming(dir string) ([]*UploadSet, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := ReadChangesDir(dir)
+	errs := MultiError{}
+	if multi, ok := err.(MultiError); ok {
+		errs = append(errs, multi...)
+	} else if err != nil {
+		return nil, err
+	}
+
+	ret := []*UploadSet{}
+	for _, el := range changes {
+		upload, err := incomingUpload(dir, el)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(el.Filename), err))
+			continue
+		}
+		ret = append(ret, upload)
+	}
+
+	if len(errs) != 0 {
+		return ret, errs
+	}
+	return ret, nil
+}
+
+func incomingUpload(dir string, changes *Changes) (*UploadSet, error) {
+	ret := UploadSet{Changes: changes, Files: map[string]string{}}
+	for _, file := range changes.Files {
+		name := file.Filename
+		if name == "" || name == "." || name == ".." || name != filepath.Base(name) {
+			return nil, fmt.Errorf("Bad filename '%s'", name)
+		}
+
+		// Lstat, so a symlink out of the incoming directory isn't
+		// mistaken for a file of the upload.
+		path := filepath.Join(dir, name)
+		info, err := os.Lstat(path)
+		if errors.Is(err, fs.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+			return nil, fmt.Errorf("%w: '%s'", ErrMissingFile, name)
+		} else if err != nil {
+			return nil, err
+		}
+		ret.Files[name] = path
+	}
+	return &ret, nil
+}
+
+// }}}
+
 // Given a bufio.Reader, consume the Reader, and return a Changes object
 // for use. The "path" argument is used to set Changes.Filename, which
 // is used by Changes.GetDSC, Changes.Remove, Changes.Move and Changes.Copy to
@@ -124,6 +239,100 @@ func ParseChanges(reader *bufio.Reader, path string) (*Changes, error) {
 	return ret, Unmarshal(ret, reader)
 }
 
+// {{{ Urgency
+
+// Urgency is the typed form of the .changes Urgency field, as defined
+// by Debian Policy, section 5.6.17.
+type Urgency int
+
+const (
+	UrgencyLow Urgency = iota
+	UrgencyMedium
+	UrgencyHigh
+	UrgencyEmergency
+	UrgencyCritical
+)
+
+var urgencyNames = map[Urgency]string{
+	UrgencyLow:       "low",
+	UrgencyMedium:    "medium",
+	UrgencyHigh:      "high",
+	UrgencyEmergency: "emergency",
+	UrgencyCritical:  "critical",
+}
+
+func (u Urgency) String() string {
+	if name, ok := urgencyNames[u]; ok {
+		return name
+	}
+	return fmt.Sprintf("Urgency(%d)", int(u))
+}
+
+// Parse the Urgency field of the .changes into a typed Urgency. Any
+// metadata following the urgency keyword (such as ` bug=1234`) is ignored,
+// and the keyword itself is compared case-insensitively.
+func (changes *Changes) UrgencyLevel() (Urgency, error) {
+	keyword := strings.TrimSpace(changes.Urgency)
+	if i := strings.IndexAny(keyword, " \t;("); i != -1 {
+		keyword = keyword[:i]
+	}
+	keyword = strings.ToLower(keyword)
+	for urgency, name := range urgencyNames {
+		if name == keyword {
+			return urgency, nil
+		}
+	}
+	return UrgencyLow, fmt.Errorf("Unknown urgency: '%s'", changes.Urgency)
+}
+
+// Split the Urgency field of the .changes into the urgency keyword and
+// any semicolon separated `key=value` metadata following it, such as
+// `medium; bug=789012`. If there is no metadata, the returned map is empty.
+func (changes *Changes) UrgencyWithMeta() (string, map[string]string, error) {
+	parts := strings.Split(changes.Urgency, ";")
+	urgency := strings.TrimSpace(parts[0])
+	if urgency == "" {
+		return "", nil, fmt.Errorf("Empty urgency: '%s'", changes.Urgency)
+	}
+
+	meta := map[string]string{}
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		els := strings.SplitN(part, "=", 2)
+		if len(els) != 2 || strings.TrimSpace(els[0]) == "" {
+			return "", nil, fmt.Errorf("Bad urgency metadata: '%s'", part)
+		}
+		meta[strings.TrimSpace(els[0])] = strings.TrimSpace(els[1])
+	}
+	return urgency, meta, nil
+}
+
+// Set the Urgency field of the .changes from the urgency keyword and
+// metadata, as returned by Changes.UrgencyWithMeta. Metadata is written
+// sorted by key, so that the output is stable.
+func (changes *Changes) SetUrgencyWithMeta(urgency string, meta map[string]string) {
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	value := urgency
+	for _, key := range keys {
+		value += fmt.Sprintf("; %s=%s", key, meta[key])
+	}
+
+	changes.Urgency = value
+	if changes.Values != nil {
+		changes.Set("Urgency", value)
+	}
+}
+
+// }}}
+
 // Return a list of FileListChangesFileHash entries from the `changes.Files`
 // entry, with the exception that each `Filename` will be joined to the root
 // directory of the Changes file.
@@ -139,6 +348,92 @@ func (changes *Changes) AbsFiles() []FileListChangesFileHash {
 	return ret
 }
 
+// {{{ Files by type
+
+var tarFileRegexp = regexp.MustCompile(`\.tar(\.[a-z0-9]+)?$`)
+
+func (changes *Changes) filterFiles(keep func(string) bool) []FileListChangesFileHash {
+	ret := []FileListChangesFileHash{}
+	for _, file := range changes.Files {
+		if keep(file.Filename) {
+			ret = append(ret, file)
+		}
+	}
+	return ret
+}
+
+// Return the binary packages (`.deb` and `.udeb` files) listed in the
+// `changes.Files` entry.
+func (changes *Changes) DebFiles() []FileListChangesFileHash {
+	return changes.filterFiles(func(name string) bool {
+		return strings.HasSuffix(name, ".deb") || strings.HasSuffix(name, ".udeb")
+	})
+}
+
+// Return the `.dsc` files listed in the `changes.Files` entry.
+func (changes *Changes) DscFiles() []FileListChangesFileHash {
+	return changes.filterFiles(func(name string) bool {
+		return strings.HasSuffix(name, ".dsc")
+	})
+}
+
+// Return the tarballs (`.tar`, or `.tar` with any compression suffix, such
+// as `.tar.xz`) listed in the `changes.Files` entry. This includes both
+// the upstream and Debian tarballs.
+func (changes *Changes) TarFiles() []FileListChangesFileHash {
+	return changes.filterFiles(tarFileRegexp.MatchString)
+}
+
+// Return the upstream tarballs listed in the `changes.Files` entry, which
+// are the `.orig.tar.*` tarball, and any `.orig-component.tar.*` tarballs.
+func (changes *Changes) OrigFiles() []FileListChangesFileHash {
+	return changes.filterFiles(func(name string) bool {
+		return tarFileRegexp.MatchString(name) &&
+			(strings.Contains(name, ".orig.tar") || strings.Contains(name, ".orig-"))
+	})
+}
+
+// Check to see if a file listed in a .changes is part of the source
+// package: the .dsc, the upstream and Debian tarballs (or a native
+// tarball), the upstream signatures, a 1.0 format .diff.gz, or the
+// .buildinfo which dpkg-genchanges includes in every upload.
+func isSourceFile(name string) bool {
+	name = strings.TrimSuffix(name, ".asc")
+	return strings.HasSuffix(name, ".dsc") ||
+		strings.HasSuffix(name, ".diff.gz") ||
+		strings.HasSuffix(name, ".buildinfo") ||
+		tarFileRegexp.MatchString(name)
+}
+
+// Check to see if the .changes describes a source-only upload, that is,
+// the Architecture is just `source`, and the Files are all part of the
+// source package (see isSourceFile), with no binary packages at all.
+func (changes *Changes) IsSourceOnly() bool {
+	if len(changes.Architectures) == 0 {
+		return false
+	}
+	for _, arch := range changes.Architectures {
+		if arch.CPU != "source" {
+			return false
+		}
+	}
+	return len(changes.filterFiles(isSourceFile)) == len(changes.Files)
+}
+
+// Check to see if the .changes describes a binary-only upload (such as a
+// binNMU, or a build from a buildd), that is, one with binary packages,
+// but without the `source` Architecture, or a .dsc.
+func (changes *Changes) IsBinaryOnly() bool {
+	for _, arch := range changes.Architectures {
+		if arch.CPU == "source" {
+			return false
+		}
+	}
+	return len(changes.DscFiles()) == 0 && len(changes.DebFiles()) > 0
+}
+
+// }}}
+
 // Return a DSC struct for the DSC listed in the .changes file. This requires
 // Changes.Filename to be correctly set, and for the .dsc file to exist
 // in the correct place next to the .changes.
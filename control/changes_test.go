@@ -22,10 +22,14 @@ package control_test
 
 import (
 	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/dependency"
 )
 
 /*
@@ -135,4 +139,234 @@ Files:
 	assert(t, len(changes.Files) == 2)
 }
 
+func TestChangesFilesByType(t *testing.T) {
+	changes := control.Changes{}
+	for _, name := range []string{
+		"hello_2.10-3.dsc",
+		"hello_2.10.orig.tar.gz",
+		"hello_2.10.orig.tar.gz.asc",
+		"hello_2.10.orig-po.tar.xz",
+		"hello_2.10-3.debian.tar.xz",
+		"hello_2.10-3_amd64.deb",
+		"hello-udeb_2.10-3_amd64.udeb",
+		"hello_2.10-3_amd64.buildinfo",
+	} {
+		file := control.FileListChangesFileHash{}
+		file.Filename = name
+		changes.Files = append(changes.Files, file)
+	}
+
+	debs := changes.DebFiles()
+	assert(t, len(debs) == 2)
+	assert(t, debs[1].Filename == "hello-udeb_2.10-3_amd64.udeb")
+
+	dscs := changes.DscFiles()
+	assert(t, len(dscs) == 1)
+	assert(t, dscs[0].Filename == "hello_2.10-3.dsc")
+
+	tars := changes.TarFiles()
+	assert(t, len(tars) == 3)
+	assert(t, tars[2].Filename == "hello_2.10-3.debian.tar.xz")
+
+	origs := changes.OrigFiles()
+	assert(t, len(origs) == 2)
+	assert(t, origs[0].Filename == "hello_2.10.orig.tar.gz")
+	assert(t, origs[1].Filename == "hello_2.10.orig-po.tar.xz")
+
+	assert(t, len((&control.Changes{}).DebFiles()) == 0)
+}
+
+func TestChangesUploadType(t *testing.T) {
+	newChanges := func(architecture string, files ...string) *control.Changes {
+		changes := control.Changes{}
+		arches, err := dependency.ParseArchitectures(architecture)
+		isok(t, err)
+		changes.Architectures = arches
+		for _, name := range files {
+			file := control.FileListChangesFileHash{}
+			file.Filename = name
+			changes.Files = append(changes.Files, file)
+		}
+		return &changes
+	}
+
+	changes := newChanges("source",
+		"hello_2.10-3.dsc",
+		"hello_2.10.orig.tar.gz",
+		"hello_2.10.orig.tar.gz.asc",
+		"hello_2.10-3.debian.tar.xz",
+		"hello_2.10-3_source.buildinfo",
+	)
+	assert(t, changes.IsSourceOnly())
+	assert(t, !changes.IsBinaryOnly())
+
+	changes = newChanges("source amd64",
+		"hello_2.10-3.dsc",
+		"hello_2.10-3.debian.tar.xz",
+		"hello_2.10-3_amd64.deb",
+	)
+	assert(t, !changes.IsSourceOnly())
+	assert(t, !changes.IsBinaryOnly())
+
+	changes = newChanges("source", "hello_2.10-3.dsc", "hello_2.10-3_amd64.deb")
+	assert(t, !changes.IsSourceOnly())
+
+	changes = newChanges("amd64",
+		"hello_2.10-3+b1_amd64.deb",
+		"hello_2.10-3+b1_amd64.buildinfo",
+	)
+	assert(t, !changes.IsSourceOnly())
+	assert(t, changes.IsBinaryOnly())
+
+	changes = newChanges("", "hello_2.10-3.dsc")
+	assert(t, !changes.IsSourceOnly())
+	assert(t, !changes.IsBinaryOnly())
+}
+
+func TestChangesUrgencyLevel(t *testing.T) {
+	for value, expected := range map[string]control.Urgency{
+		"low":                  control.UrgencyLow,
+		"medium":               control.UrgencyMedium,
+		"HIGH":                 control.UrgencyHigh,
+		"emergency bug=783746": control.UrgencyEmergency,
+		"critical":             control.UrgencyCritical,
+	} {
+		changes := control.Changes{Urgency: value}
+		urgency, err := changes.UrgencyLevel()
+		isok(t, err)
+		assert(t, urgency == expected)
+	}
+
+	changes := control.Changes{Urgency: "whenever"}
+	_, err := changes.UrgencyLevel()
+	notok(t, err)
+
+	assert(t, control.UrgencyMedium.String() == "medium")
+	assert(t, control.UrgencyCritical.String() == "critical")
+}
+
+func TestChangesUrgencyWithMeta(t *testing.T) {
+	changes := control.Changes{Urgency: "medium; bug=789012;nmu=yes"}
+	urgency, meta, err := changes.UrgencyWithMeta()
+	isok(t, err)
+	assert(t, urgency == "medium")
+	assert(t, len(meta) == 2)
+	assert(t, meta["bug"] == "789012")
+	assert(t, meta["nmu"] == "yes")
+
+	changes.SetUrgencyWithMeta(urgency, meta)
+	assert(t, changes.Urgency == "medium; bug=789012; nmu=yes")
+
+	changes = control.Changes{Urgency: "low"}
+	urgency, meta, err = changes.UrgencyWithMeta()
+	isok(t, err)
+	assert(t, urgency == "low")
+	assert(t, len(meta) == 0)
+
+	for _, bad := range []string{"", "; bug=1", "high; bug"} {
+		changes := control.Changes{Urgency: bad}
+		_, _, err := changes.UrgencyWithMeta()
+		notok(t, err)
+	}
+
+	reader := bufio.NewReader(strings.NewReader("Source: hello\nUrgency: low\n"))
+	parsed, err := control.ParseChanges(reader, "")
+	isok(t, err)
+	parsed.SetUrgencyWithMeta("high", map[string]string{"bug": "1234"})
+	assert(t, parsed.Urgency == "high; bug=1234")
+	assert(t, parsed.Values["Urgency"] == "high; bug=1234")
+}
+
+func TestReadChangesDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"hello_2.10-3_amd64.changes":    "Source: hello\nVersion: 2.10-3\n",
+		"fbautostart_2.7-1_all.changes": "Source: fbautostart\nVersion: 2.7-1\n",
+		"broken_1.0_amd64.changes":      "Source: broken\nVersion: a:1.0\n",
+		"hello_2.10-3.dsc":              "Source: hello\n",
+	} {
+		isok(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0644))
+	}
+	isok(t, os.Mkdir(filepath.Join(dir, "subdir.changes"), 0755))
+
+	changes, err := control.ReadChangesDir(dir)
+	notok(t, err)
+	multi, ok := err.(control.MultiError)
+	assert(t, ok)
+	assert(t, len(multi) == 1)
+	assert(t, strings.HasPrefix(multi.Error(), "broken_1.0_amd64.changes: "))
+
+	assert(t, len(changes) == 2)
+	assert(t, changes[0].Source == "fbautostart")
+	assert(t, changes[1].Source == "hello")
+	assert(t, changes[1].Filename == filepath.Join(dir, "hello_2.10-3_amd64.changes"))
+
+	isok(t, os.Remove(filepath.Join(dir, "broken_1.0_amd64.changes")))
+	changes, err = control.ReadChangesDir(dir)
+	isok(t, err)
+	assert(t, len(changes) == 2)
+
+	_, err = control.ReadChangesDir(filepath.Join(dir, "missing"))
+	notok(t, err)
+}
+
+func TestParseIncoming(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"hello_2.10-3_amd64.changes": `Source: hello
+Version: 2.10-3
+Files:
+ d41d8cd98f00b204e9800998ecf8427e 0 devel optional hello_2.10-3.dsc
+ d41d8cd98f00b204e9800998ecf8427e 0 devel optional hello_2.10-3_amd64.deb
+`,
+		"fbautostart_2.7-1_all.changes": `Source: fbautostart
+Version: 2.7-1
+Files:
+ d41d8cd98f00b204e9800998ecf8427e 0 misc optional fbautostart_2.7-1.dsc
+ d41d8cd98f00b204e9800998ecf8427e 0 misc optional fbautostart_2.7-1_all.deb
+`,
+		"evil_1.0_all.changes": `Source: evil
+Version: 1.0
+Files:
+ d41d8cd98f00b204e9800998ecf8427e 0 misc optional ../../etc/passwd
+`,
+		"hello_2.10-3.dsc":       "",
+		"hello_2.10-3_amd64.deb": "",
+		"fbautostart_2.7-1.dsc":  "",
+		"unrelated_1.0_all.deb":  "",
+	} {
+		isok(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0644))
+	}
+
+	uploads, err := control.ParseIncoming(dir)
+	notok(t, err)
+	multi, ok := err.(control.MultiError)
+	assert(t, ok)
+	assert(t, len(multi) == 2)
+	assert(t, strings.HasPrefix(multi[0].Error(), "evil_1.0_all.changes: "))
+	assert(t, strings.HasPrefix(multi[1].Error(), "fbautostart_2.7-1_all.changes: "))
+	assert(t, errors.Is(err, control.ErrMissingFile))
+
+	assert(t, len(uploads) == 1)
+	assert(t, uploads[0].Changes.Source == "hello")
+	assert(t, len(uploads[0].Files) == 2)
+	assert(t, uploads[0].Files["hello_2.10-3_amd64.deb"] == filepath.Join(dir, "hello_2.10-3_amd64.deb"))
+
+	isok(t, os.Remove(filepath.Join(dir, "evil_1.0_all.changes")))
+	isok(t, os.WriteFile(filepath.Join(dir, "fbautostart_2.7-1_all.deb"), nil, 0644))
+	uploads, err = control.ParseIncoming(dir)
+	isok(t, err)
+	assert(t, len(uploads) == 2)
+	assert(t, uploads[0].Changes.Source == "fbautostart")
+
+	secret := filepath.Join(t.TempDir(), "secret")
+	isok(t, os.WriteFile(secret, []byte("hunter2"), 0600))
+	isok(t, os.Remove(filepath.Join(dir, "fbautostart_2.7-1_all.deb")))
+	isok(t, os.Symlink(secret, filepath.Join(dir, "fbautostart_2.7-1_all.deb")))
+	uploads, err = control.ParseIncoming(dir)
+	assert(t, errors.Is(err, control.ErrMissingFile))
+	assert(t, len(uploads) == 1)
+	assert(t, uploads[0].Changes.Source == "hello")
+}
+
 // vim: foldmethod=marker
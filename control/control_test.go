@@ -22,6 +22,8 @@ package control_test
 
 import (
 	"bufio"
+	"fmt"
+	"io"
 	"strings"
 	"testing"
 
@@ -169,4 +171,59 @@ Description: XDG compliant autostarting app for Fluxbox
 	assert(t, c.Binaries[0].Conffiles[1].Hash == "db44b9cbb80456bc68c1225ee9e38fcb")
 }
 
+// Build a Packages index of the given number of entries, looking roughly
+// like the real thing, for benchmarking the ParagraphReader.
+func benchmarkPackagesIndex(entries int) string {
+	buf := strings.Builder{}
+	for i := 0; i < entries; i++ {
+		fmt.Fprintf(&buf, `Package: libexample%d
+Source: example
+Version: 1.%d.0-1
+Installed-Size: %d
+Maintainer: Example Maintainers <example@lists.debian.org>
+Architecture: amd64
+Multi-Arch: same
+Depends: libc6 (>= 2.34), libgcc-s1 (>= 3.0), libstdc++6 (>= 12)
+Description: example library, number %d
+ This is an example library, which exists to give the ParagraphReader
+ something to chew on. It has a long description, which spans a few
+ continuation lines.
+ .
+ Including a blank line.
+Homepage: https://example.org/
+Description-md5: 0f1a0c7e6b9c6e4bcf4c6f6fbc2a1e4d
+Section: libs
+Priority: optional
+Filename: pool/main/e/example/libexample%d_1.%d.0-1_amd64.deb
+Size: 123456
+MD5sum: 1c4a8f0f8f5a0e3b0c2d6f3b9e2a7c41
+SHA256: 6b7a8e1b4f5c3a2d1e0f9c8b7a6d5e4f3c2b1a0f9e8d7c6b5a4f3e2d1c0b9a8f
+
+`, i, i, i, i, i, i)
+	}
+	return buf.String()
+}
+
+func BenchmarkParagraphReader(b *testing.B) {
+	index := benchmarkPackagesIndex(1000)
+	b.SetBytes(int64(len(index)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		reader, err := control.NewParagraphReader(strings.NewReader(index), nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for {
+			_, err := reader.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
 // vim: foldmethod=marker
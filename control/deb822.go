@@ -0,0 +1,124 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control // import "github.com/akozlenkov/go-debian/control"
+
+import (
+	"io"
+	"strings"
+)
+
+// The fields of the deb822 style APT sources format (see sources.list(5))
+// which hold a whitespace separated list of values, which may be spread
+// over as many lines as needed, keyed by their lowercase name, since field
+// names are not case sensitive.
+var deb822MultiValueFields = map[string]bool{
+	"types":         true,
+	"uris":          true,
+	"suites":        true,
+	"components":    true,
+	"architectures": true,
+	"languages":     true,
+	"targets":       true,
+}
+
+func isDeb822MultiValueField(name string) bool {
+	return deb822MultiValueFields[strings.ToLower(name)]
+}
+
+// Parse all the Paragraphs of a deb822 style APT sources file, such as
+// /etc/apt/sources.list.d/debian.sources. The values of the multi-value
+// fields, such as Suites or Components, may be spread over several lines
+// after the field name; they're normalized so each value is separated by a
+// single space, no matter how the file laid them out, which makes them
+// easy to read back with GetSpaceList. All other fields (such as an inline
+// Signed-By key) are left as they are.
+func ParseDeb822(r io.Reader) ([]*Paragraph, error) {
+	reader, err := NewParagraphReader(r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*Paragraph{}
+	for {
+		para, err := reader.Next()
+		if err == io.EOF {
+			return ret, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range para.Order {
+			if isDeb822MultiValueField(key) {
+				para.Values[key] = strings.Join(strings.Fields(para.Values[key]), " ")
+			}
+		}
+		ret = append(ret, para)
+	}
+}
+
+// A Deb822Writer writes Paragraphs out in the deb822 style APT sources
+// format. The values of the multi-value fields, such as Suites, are
+// written on a single line separated by spaces, or, if OneValuePerLine is
+// set, with each value on its own continuation line. Any other multi-line
+// value, such as an inline Signed-By key, starts on the line after the
+// field name.
+type Deb822Writer struct {
+	OneValuePerLine bool
+
+	writer         io.Writer
+	alreadyWritten bool
+}
+
+// Create a new Deb822Writer, which writes to the given io.Writer.
+func NewDeb822Writer(w io.Writer) *Deb822Writer {
+	return &Deb822Writer{writer: w}
+}
+
+// Write a single Paragraph out. Subsequent Paragraphs are separated with a
+// blank line.
+func (w *Deb822Writer) Write(para *Paragraph) error {
+	out := strings.Builder{}
+	if w.alreadyWritten {
+		out.WriteString("\n")
+	}
+	for _, key := range para.Order {
+		value := strings.TrimRight(para.Values[key], "\n")
+		if isDeb822MultiValueField(key) {
+			values := strings.Fields(value)
+			if w.OneValuePerLine && len(values) > 1 {
+				value = "\n" + strings.Join(values, "\n")
+			} else {
+				value = strings.Join(values, " ")
+			}
+		} else if strings.Contains(value, "\n") {
+			/* Multi-line values, such as an inline Signed-By key, start
+			 * on the line after the field name. */
+			value = "\n" + value
+		}
+		out.WriteString(formatField(key, value))
+	}
+
+	w.alreadyWritten = true
+	_, err := io.WriteString(w.writer, out.String())
+	return err
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,113 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+/*
+ *
+ */
+
+// Test sources {{{
+const testDeb822Sources = `# Debian, with the updates
+Types: deb deb-src
+URIs: http://deb.debian.org/debian
+Suites:
+ bookworm
+ bookworm-updates
+Components: main
+  contrib   non-free-firmware
+Signed-By: /usr/share/keyrings/debian-archive-keyring.gpg
+
+Types: deb
+URIs: https://example.com/apt
+Suites: stable
+components: main
+Signed-By:
+ -----BEGIN PGP PUBLIC KEY BLOCK-----
+ .
+ mDMEZb8ZfRYJKwYBBAHaRw8BAQdAtest
+ -----END PGP PUBLIC KEY BLOCK-----
+`
+
+// }}}
+
+func TestParseDeb822(t *testing.T) {
+	paras, err := control.ParseDeb822(strings.NewReader(testDeb822Sources))
+	isok(t, err)
+	assert(t, len(paras) == 2)
+
+	assert(t, paras[0].Values["Types"] == "deb deb-src")
+	assert(t, paras[0].Values["Suites"] == "bookworm bookworm-updates")
+	assert(t, strings.Join(paras[0].GetSpaceList("Components"), ",") == "main,contrib,non-free-firmware")
+	assert(t, paras[0].Values["Signed-By"] == "/usr/share/keyrings/debian-archive-keyring.gpg")
+
+	assert(t, paras[1].Values["components"] == "main")
+	assert(t, strings.HasPrefix(paras[1].Values["Signed-By"], "-----BEGIN PGP PUBLIC KEY BLOCK-----\n\nmDMEZb8Z"))
+}
+
+func TestDeb822Writer(t *testing.T) {
+	paras, err := control.ParseDeb822(strings.NewReader(testDeb822Sources))
+	isok(t, err)
+
+	out := bytes.Buffer{}
+	writer := control.NewDeb822Writer(&out)
+	for _, para := range paras {
+		isok(t, writer.Write(para))
+	}
+	written := out.String()
+	assert(t, strings.HasPrefix(written, `Types: deb deb-src
+URIs: http://deb.debian.org/debian
+Suites: bookworm bookworm-updates
+Components: main contrib non-free-firmware
+Signed-By: /usr/share/keyrings/debian-archive-keyring.gpg
+
+Types: deb
+`))
+
+	assert(t, strings.HasSuffix(written, "Signed-By: \n -----BEGIN PGP PUBLIC KEY BLOCK-----\n .\n"+
+		" mDMEZb8ZfRYJKwYBBAHaRw8BAQdAtest\n -----END PGP PUBLIC KEY BLOCK-----\n"))
+
+	reparsed, err := control.ParseDeb822(strings.NewReader(written))
+	isok(t, err)
+	assert(t, len(reparsed) == 2)
+	for i := range paras {
+		assert(t, strings.Join(reparsed[i].Order, " ") == strings.Join(paras[i].Order, " "))
+		for key, value := range paras[i].Values {
+			assert(t, reparsed[i].Values[key] == value)
+		}
+	}
+
+	out.Reset()
+	writer = control.NewDeb822Writer(&out)
+	writer.OneValuePerLine = true
+	isok(t, writer.Write(paras[0]))
+	assert(t, strings.Contains(out.String(), "Suites: \n bookworm\n bookworm-updates\n"))
+	assert(t, strings.Contains(out.String(), "URIs: http://deb.debian.org/debian\n"))
+}
+
+// vim: foldmethod=marker
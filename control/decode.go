@@ -53,7 +53,10 @@ type Unmarshallable interface {
 // This code will attempt to unpack it into the struct based on the
 // literal name of the key, compared byte-for-byte. If this is not
 // OK, the struct tag `control:""` can be used to define the key to use
-// in the RFC822 stream.
+// in the RFC822 stream. Much like encoding/json, a field tagged with
+// `control:"-"` is skipped entirely, and any options following a comma
+// in the tag (such as `control:"Name,omitempty"`) are ignored when
+// unpacking.
 //
 // If you're unpacking into a list of strings, you have the option of defining
 // a string to split tokens on (`delim:", "`), and things to strip off each
@@ -130,6 +133,24 @@ func decode(p *ParagraphReader, into reflect.Value) error {
 
 // Top-level struct dispatch {{{
 
+// Return the key of the struct field in the RFC822 stream, which is the
+// name of the field unless overridden by the `control:""` struct tag, and
+// whether the tag has the `omitempty` option set, such as
+// `control:"Name,omitempty"` or `control:",omitempty"`.
+func controlTag(fieldType reflect.StructField) (key string, omitempty bool) {
+	name, options, _ := strings.Cut(fieldType.Tag.Get("control"), ",")
+	key = fieldType.Name
+	if name != "" {
+		key = name
+	}
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return key, omitempty
+}
+
 func decodeStruct(p Paragraph, into reflect.Value) error {
 	/* If we have a pointer, let's follow it */
 	if into.Type().Kind() == reflect.Ptr {
@@ -155,10 +176,7 @@ func decodeStruct(p Paragraph, into reflect.Value) error {
 
 		/* First, let's get the name of the field as we'd index into the
 		 * map[string]string. */
-		paragraphKey := fieldType.Name
-		if it := fieldType.Tag.Get("control"); it != "" {
-			paragraphKey = it
-		}
+		paragraphKey, _ := controlTag(fieldType)
 
 		if paragraphKey == "-" {
 			/* If the key is "-", lets go ahead and skip it */
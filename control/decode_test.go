@@ -133,3 +133,16 @@ Extra-Source-Only: yes
 `)))
 	assert(t, foo.ExtraSourceOnly)
 }
+
+func TestIgnoreAndOmitEmptyUnmarshal(t *testing.T) {
+	foo := omitEmptyStruct{}
+	isok(t, control.Unmarshal(&foo, strings.NewReader(`Package: hello
+Essential: yes
+Installed-Size: 280
+Internal: not-for-you
+`)))
+	assert(t, foo.Package == "hello")
+	assert(t, foo.Essential)
+	assert(t, foo.InstalledSize == 280)
+	assert(t, foo.Internal == "")
+}
@@ -0,0 +1,115 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control // import "github.com/akozlenkov/go-debian/control"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Field is a single key value pair out of a Paragraph.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// A FieldChange is a Field which exists in both Paragraphs being compared,
+// but with a different value in each.
+type FieldChange struct {
+	Name string
+	Old  string
+	New  string
+}
+
+// A ParagraphDiff is the structured difference between two Paragraphs, such
+// as two versions of the same package's stanza in a Packages file. Added
+// and Changed entries are in the order of the new Paragraph, and Removed
+// entries are in the order of the old Paragraph.
+type ParagraphDiff struct {
+	Added   []Field
+	Removed []Field
+	Changed []FieldChange
+}
+
+// Compare two Paragraphs, and return the set of Fields that were added,
+// removed, or changed going from the `old` Paragraph to the `new` one.
+func DiffParagraphs(old, new *Paragraph) *ParagraphDiff {
+	ret := ParagraphDiff{
+		Added:   []Field{},
+		Removed: []Field{},
+		Changed: []FieldChange{},
+	}
+
+	for _, key := range old.Order {
+		if _, ok := new.Values[key]; !ok {
+			ret.Removed = append(ret.Removed, Field{Name: key, Value: old.Values[key]})
+		}
+	}
+
+	for _, key := range new.Order {
+		oldValue, ok := old.Values[key]
+		newValue := new.Values[key]
+		if !ok {
+			ret.Added = append(ret.Added, Field{Name: key, Value: newValue})
+			continue
+		}
+		if oldValue != newValue {
+			ret.Changed = append(ret.Changed, FieldChange{
+				Name: key,
+				Old:  oldValue,
+				New:  newValue,
+			})
+		}
+	}
+
+	return &ret
+}
+
+// Check to see if the two Paragraphs compared were identical.
+func (d *ParagraphDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Return a human readable version of the difference, in a form loosely
+// modeled on a unified diff, with one `-` or `+` line per field value.
+func (d *ParagraphDiff) String() string {
+	var out strings.Builder
+	for _, field := range d.Removed {
+		fmt.Fprintf(&out, "-%s: %s\n", field.Name, indentDiffValue("-", field.Value))
+	}
+	for _, change := range d.Changed {
+		fmt.Fprintf(&out, "-%s: %s\n", change.Name, indentDiffValue("-", change.Old))
+		fmt.Fprintf(&out, "+%s: %s\n", change.Name, indentDiffValue("+", change.New))
+	}
+	for _, field := range d.Added {
+		fmt.Fprintf(&out, "+%s: %s\n", field.Name, indentDiffValue("+", field.Value))
+	}
+	return out.String()
+}
+
+// Prefix each continuation line of a multiline value, so that it's still
+// clear which side of the diff it belongs to.
+func indentDiffValue(prefix, value string) string {
+	value = strings.TrimSuffix(value, "\n")
+	return strings.Replace(value, "\n", "\n"+prefix+" ", -1)
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,73 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+/*
+ *
+ */
+
+func TestDiffParagraphs(t *testing.T) {
+	reader, err := control.NewParagraphReader(strings.NewReader(`Package: hello
+Version: 2.10-2
+Installed-Size: 280
+Tag: devel::lang:c
+
+Package: hello
+Version: 2.10-3
+Installed-Size: 280
+Homepage: http://www.gnu.org/software/hello/
+`), nil)
+	isok(t, err)
+	paragraphs, err := reader.All()
+	isok(t, err)
+	assert(t, len(paragraphs) == 2)
+
+	diff := control.DiffParagraphs(&paragraphs[0], &paragraphs[1])
+	assert(t, !diff.IsEmpty())
+	assert(t, len(diff.Added) == 1)
+	assert(t, diff.Added[0].Name == "Homepage")
+	assert(t, len(diff.Removed) == 1)
+	assert(t, diff.Removed[0].Name == "Tag")
+	assert(t, diff.Removed[0].Value == "devel::lang:c")
+	assert(t, len(diff.Changed) == 1)
+	assert(t, diff.Changed[0] == control.FieldChange{
+		Name: "Version",
+		Old:  "2.10-2",
+		New:  "2.10-3",
+	})
+	assert(t, diff.String() == `-Tag: devel::lang:c
+-Version: 2.10-2
++Version: 2.10-3
++Homepage: http://www.gnu.org/software/hello/
+`)
+
+	assert(t, control.DiffParagraphs(&paragraphs[0], &paragraphs[0]).IsEmpty())
+	assert(t, control.DiffParagraphs(&paragraphs[0], &paragraphs[0]).String() == "")
+}
+
+// vim: foldmethod=marker
@@ -166,6 +166,31 @@ func (d *DSC) HasArchAll() bool {
 	return false
 }
 
+// Check to see if this .dsc describes a native source package, that is,
+// one without a separate upstream tarball.
+//
+// The "3.0 (native)" format is always native, and the "1.0" format is
+// native unless a .diff.gz is shipped alongside the tarball. If the .dsc
+// has no Format field at all, fall back to checking the Version for a
+// Debian revision.
+func (d *DSC) IsNativePackage() bool {
+	switch d.Format {
+	case "3.0 (native)":
+		return true
+	case "1.0":
+		for _, file := range d.Files {
+			if strings.HasSuffix(file.Filename, ".diff.gz") {
+				return false
+			}
+		}
+		return true
+	case "":
+		return d.Version.IsNative()
+	default:
+		return false
+	}
+}
+
 // Return a list of all entities that are responsible for the package's
 // well being. The 0th element is always the package's Maintainer,
 // with any Uploaders following.
@@ -173,6 +198,31 @@ func (d *DSC) Maintainers() []string {
 	return append([]string{d.Maintainer}, d.Uploaders...)
 }
 
+// Split the Maintainer of the .dsc into the display name and the email
+// address.
+func (d *DSC) MaintainerContact() (name, email string, err error) {
+	return ParseEmailAddress(d.Maintainer)
+}
+
+// Parse the Uploaders field of the .dsc into a list of Authors. The
+// Uploaders member of the DSC is split on whitespace, which doesn't work for
+// addresses, so this reads the Uploaders field as it was in the .dsc, where
+// it's available.
+func (d *DSC) UploaderAddresses() ([]Author, error) {
+	uploaders, ok := d.Values["Uploaders"]
+	if !ok {
+		uploaders = strings.Join(d.Uploaders, " ")
+	}
+	return parseAuthors(uploaders)
+}
+
+// Format a name and email address in the form expected by the Maintainer
+// field, this is the inverse of DSC.MaintainerContact. As with
+// FormatEmailAddress, control characters are rejected with an error.
+func FormatMaintainer(name, email string) (string, error) {
+	return FormatEmailAddress(name, email)
+}
+
 // Return a list of MD5FileHash entries from the `dsc.Files`
 // entry, with the exception that each `Filename` will be joined to the root
 // directory of the DSC file.
@@ -203,4 +203,137 @@ Files:
 	assert(t, c.HasArchAll())
 }
 
+func TestDSCNativePackage(t *testing.T) {
+	// Test DSC (3.0 (quilt)) {{{
+	reader := bufio.NewReader(strings.NewReader(`Format: 3.0 (quilt)
+Source: fbautostart
+Binary: fbautostart
+Architecture: any
+Version: 2.718281828-1
+Maintainer: Paul Tagliamonte <paultag@ubuntu.com>
+Files:
+ 06495f9b23b1c9b1bf35c2346cb48f63 92748 fbautostart_2.718281828.orig.tar.gz
+ f58c0e0bf4d56461e776232484c07301 2356 fbautostart_2.718281828-1.debian.tar.xz
+`))
+	// }}}
+	c, err := control.ParseDsc(reader, "")
+	isok(t, err)
+	assert(t, !c.IsNativePackage())
+
+	// Test DSC (3.0 (native)) {{{
+	reader = bufio.NewReader(strings.NewReader(`Format: 3.0 (native)
+Source: dput-ng
+Binary: dput-ng
+Architecture: all
+Version: 1.9
+Maintainer: dput-ng Maintainers <dput-ng-maint@lists.alioth.debian.org>
+Files:
+ 06495f9b23b1c9b1bf35c2346cb48f63 92748 dput-ng_1.9.tar.xz
+`))
+	// }}}
+	c, err = control.ParseDsc(reader, "")
+	isok(t, err)
+	assert(t, c.IsNativePackage())
+
+	// Test DSC (1.0, with and without a diff) {{{
+	reader = bufio.NewReader(strings.NewReader(`Format: 1.0
+Source: hello
+Version: 2.10-1
+Files:
+ 06495f9b23b1c9b1bf35c2346cb48f63 92748 hello_2.10.orig.tar.gz
+ f58c0e0bf4d56461e776232484c07301 2356 hello_2.10-1.diff.gz
+`))
+	// }}}
+	c, err = control.ParseDsc(reader, "")
+	isok(t, err)
+	assert(t, !c.IsNativePackage())
+
+	reader = bufio.NewReader(strings.NewReader(`Format: 1.0
+Source: hello
+Version: 2.10
+Files:
+ 06495f9b23b1c9b1bf35c2346cb48f63 92748 hello_2.10.tar.gz
+`))
+	c, err = control.ParseDsc(reader, "")
+	isok(t, err)
+	assert(t, c.IsNativePackage())
+
+	// Test DSC (no Format) {{{
+	reader = bufio.NewReader(strings.NewReader(`Source: hello
+Version: 2.10-1
+`))
+	// }}}
+	c, err = control.ParseDsc(reader, "")
+	isok(t, err)
+	assert(t, !c.IsNativePackage())
+}
+
+func TestDSCMaintainerContact(t *testing.T) {
+	for maintainer, expected := range map[string][2]string{
+		"Paul Tagliamonte <paultag@ubuntu.com>":                     {"Paul Tagliamonte", "paultag@ubuntu.com"},
+		"Debian Go Packaging Team <team+pkg-go@tracker.d.o>":        {"Debian Go Packaging Team", "team+pkg-go@tracker.d.o"},
+		"Tagliamonte, Paul <paultag@debian.org>":                    {"Tagliamonte, Paul", "paultag@debian.org"},
+		`"Tagliamonte, Paul" <paultag@debian.org>`:                  {"Tagliamonte, Paul", "paultag@debian.org"},
+		"paultag@debian.org":                                        {"", "paultag@debian.org"},
+		"paultag@debian.org (Paul Tagliamonte)":                     {"Paul Tagliamonte", "paultag@debian.org"},
+		"=?UTF-8?Q?J=C3=B6rg_Frings-F=C3=BCrst?= <debian@j-f-f.de>": {"Jörg Frings-Fürst", "debian@j-f-f.de"},
+	} {
+		dsc := control.DSC{Maintainer: maintainer}
+		name, email, err := dsc.MaintainerContact()
+		isok(t, err)
+		assert(t, name == expected[0])
+		assert(t, email == expected[1])
+	}
+
+	dsc := control.DSC{Maintainer: "Paul Tagliamonte"}
+	_, _, err := dsc.MaintainerContact()
+	notok(t, err)
+}
+
+func TestDSCUploaderAddresses(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader(`Format: 3.0 (quilt)
+Source: fbautostart
+Version: 2.718281828-1
+Maintainer: Paul Tagliamonte <paultag@ubuntu.com>
+Uploaders: "Tagliamonte, Paul" <paultag@debian.org>,
+ Tianon Gravi <tianon@debian.org>, admwiggin@gmail.com (Andrew Wiggin),
+`))
+	dsc, err := control.ParseDsc(reader, "")
+	isok(t, err)
+
+	uploaders, err := dsc.UploaderAddresses()
+	isok(t, err)
+	assert(t, len(uploaders) == 3)
+	assert(t, uploaders[0] == control.Author{Name: "Tagliamonte, Paul", Email: "paultag@debian.org"})
+	assert(t, uploaders[1] == control.Author{Name: "Tianon Gravi", Email: "tianon@debian.org"})
+	assert(t, uploaders[2] == control.Author{Name: "Andrew Wiggin", Email: "admwiggin@gmail.com"})
+
+	dsc = &control.DSC{Uploaders: []string{"Tianon", "Gravi", "<tianon@debian.org>"}}
+	uploaders, err = dsc.UploaderAddresses()
+	isok(t, err)
+	assert(t, len(uploaders) == 1)
+	assert(t, uploaders[0].Name == "Tianon Gravi")
+
+	dsc = &control.DSC{}
+	uploaders, err = dsc.UploaderAddresses()
+	isok(t, err)
+	assert(t, len(uploaders) == 0)
+
+	dsc = &control.DSC{Uploaders: []string{"Tianon,", "Gravi"}}
+	_, err = dsc.UploaderAddresses()
+	notok(t, err)
+}
+
+func TestFormatMaintainer(t *testing.T) {
+	for _, el := range [][3]string{
+		{"Paul Tagliamonte", "paultag@debian.org", "Paul Tagliamonte <paultag@debian.org>"},
+		{"Tagliamonte, Paul", "paultag@debian.org", `"Tagliamonte, Paul" <paultag@debian.org>`},
+		{"", "paultag@debian.org", "paultag@debian.org"},
+	} {
+		maintainer, err := control.FormatMaintainer(el[0], el[1])
+		isok(t, err)
+		assert(t, maintainer == el[2])
+	}
+}
+
 // vim: foldmethod=marker
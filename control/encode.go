@@ -79,16 +79,17 @@ func convertToParagraph(data reflect.Value) (*Paragraph, error) {
 			continue
 		}
 
-		paragraphKey := fieldType.Name
-		if it := fieldType.Tag.Get("control"); it != "" {
-			paragraphKey = it
-		}
+		paragraphKey, omitempty := controlTag(fieldType)
 
 		if paragraphKey == "-" {
 			/* If the key is "-", lets go ahead and skip it */
 			continue
 		}
 
+		if omitempty && field.IsZero() {
+			continue
+		}
+
 		data, err := marshalStructValue(field, fieldType)
 		if err != nil {
 			return nil, err
@@ -201,7 +202,10 @@ func marshalStructValueSlice(field reflect.Value, fieldType reflect.StructField)
 //
 // This code will attempt to unpack it into the struct based on the
 // literal name of the key, This can be overridden by the struct tag
-// `control:""`.
+// `control:""`. Fields tagged `control:"-"` are never written, and fields
+// tagged with the `omitempty` option (`control:"Name,omitempty"`) are not
+// written if they have the zero value, such as `false` or `0`. Empty
+// strings and lists are never written, unless the field is required.
 //
 // If you're dehydrating a list of strings, you have the option of defining
 // a string to join the tokens with (`delim:", "`).
@@ -238,7 +242,10 @@ func Marshal(writer io.Writer, data interface{}) error {
 //
 // This code will attempt to unpack it into the struct based on the
 // literal name of the key, This can be overridden by the struct tag
-// `control:""`.
+// `control:""`. Fields tagged `control:"-"` are never written, and fields
+// tagged with the `omitempty` option (`control:"Name,omitempty"`) are not
+// written if they have the zero value, such as `false` or `0`. Empty
+// strings and lists are never written, unless the field is required.
 //
 // If you're dehydrating a list of strings, you have the option of defining
 // a string to join the tokens with (`delim:", "`).
@@ -250,15 +257,33 @@ func Marshal(writer io.Writer, data interface{}) error {
 type Encoder struct {
 	writer         io.Writer
 	alreadyWritten bool
+	options        EncoderOptions
+}
+
+// EncoderOptions control the output of an Encoder beyond the content of
+// the Paragraphs themselves.
+type EncoderOptions struct {
+	// If TrailingNewline is set, every Paragraph (including the last one)
+	// is followed by a blank line, so that the output may be directly
+	// concatenated with more Paragraphs. By default, the blank line is only
+	// written between Paragraphs.
+	TrailingNewline bool
 }
 
 // NewEncoder {{{
 
 // Create a new Encoder, which is configured to write to the given `io.Writer`.
 func NewEncoder(writer io.Writer) (*Encoder, error) {
+	return NewEncoderWithOptions(writer, EncoderOptions{})
+}
+
+// Create a new Encoder, which is configured to write to the given
+// `io.Writer`, with the given EncoderOptions.
+func NewEncoderWithOptions(writer io.Writer, options EncoderOptions) (*Encoder, error) {
 	return &Encoder{
 		writer:         writer,
 		alreadyWritten: false,
+		options:        options,
 	}, nil
 }
 
@@ -307,7 +332,7 @@ func (e *Encoder) encodeSlice(data reflect.Value) error {
 // Encode a Struct {{{
 
 func (e *Encoder) encodeStruct(data reflect.Value) error {
-	if e.alreadyWritten {
+	if e.alreadyWritten && !e.options.TrailingNewline {
 		_, err := e.writer.Write([]byte("\n"))
 		if err != nil {
 			return err
@@ -318,7 +343,14 @@ func (e *Encoder) encodeStruct(data reflect.Value) error {
 		return err
 	}
 	e.alreadyWritten = true
-	return paragraph.WriteTo(e.writer)
+	if err := paragraph.WriteTo(e.writer); err != nil {
+		return err
+	}
+	if e.options.TrailingNewline {
+		_, err := e.writer.Write([]byte("\n"))
+		return err
+	}
+	return nil
 }
 
 // }}}
@@ -112,6 +112,35 @@ A Test`}
 `)
 }
 
+func TestEncoderTrailingNewline(t *testing.T) {
+	testStruct := TestMarshalStruct{Foo: "Hello"}
+
+	writer := bytes.Buffer{}
+	encoder, err := control.NewEncoderWithOptions(&writer, control.EncoderOptions{
+		TrailingNewline: true,
+	})
+	isok(t, err)
+	isok(t, encoder.Encode(testStruct))
+	isok(t, encoder.Encode([]TestMarshalStruct{testStruct, testStruct}))
+	assert(t, writer.String() == `Foo: Hello
+
+Foo: Hello
+
+Foo: Hello
+
+`)
+
+	writer = bytes.Buffer{}
+	encoder, err = control.NewEncoderWithOptions(&writer, control.EncoderOptions{})
+	isok(t, err)
+	isok(t, encoder.Encode(testStruct))
+	isok(t, encoder.Encode(testStruct))
+	assert(t, writer.String() == `Foo: Hello
+
+Foo: Hello
+`)
+}
+
 type boolStruct struct {
 	ExtraSourceOnly bool `control:"Extra-Source-Only"`
 }
@@ -136,4 +165,36 @@ func TestBoolMarshal(t *testing.T) {
 `)
 }
 
+type omitEmptyStruct struct {
+	Package       string
+	Essential     bool `control:",omitempty"`
+	InstalledSize int  `control:"Installed-Size,omitempty"`
+	Protected     bool
+	Internal      string `control:"-"`
+}
+
+func TestOmitEmptyMarshal(t *testing.T) {
+	el := omitEmptyStruct{Package: "hello", Internal: "secret"}
+
+	writer := bytes.Buffer{}
+	isok(t, control.Marshal(&writer, el))
+	assert(t, writer.String() == `Package: hello
+Protected: no
+`)
+
+	typed := bytes.Buffer{}
+	isok(t, control.NewTypedWriter[omitEmptyStruct](&typed).Write(el))
+	assert(t, typed.String() == writer.String())
+
+	el.Essential = true
+	el.InstalledSize = 280
+	writer = bytes.Buffer{}
+	isok(t, control.Marshal(&writer, el))
+	assert(t, writer.String() == `Package: hello
+Essential: yes
+Installed-Size: 280
+Protected: no
+`)
+}
+
 // vim: foldmethod=marker
@@ -110,6 +110,58 @@ func (c *FileHash) Verifier() (io.WriteCloser, error) {
 	return &verifier{h: h, want: sum}, nil
 }
 
+// StreamVerify wraps the given io.Reader, returning an io.Reader which
+// hashes the data as it passes through, along with a function to be called
+// once the reader has been fully consumed. That function returns an error if
+// the data read did not match every one of the expected FileHash entries,
+// which makes this suitable for checking a download before it has been
+// written to disk. If expected is empty, nothing would be checked, so the
+// function always returns an error.
+//
+// Example:
+//
+//	r, verify := control.StreamVerify(resp.Body, expected)
+//	if _, err := io.Copy(f, r); err != nil {
+//	    return err
+//	}
+//	if err := verify(); err != nil {
+//	    return err
+//	}
+func StreamVerify(r io.Reader, expected FileHashes) (io.Reader, func() error) {
+	if len(expected) == 0 {
+		return r, func() error { return fmt.Errorf("No hashes to verify against") }
+	}
+
+	hashers := make([]*hashio.Hasher, len(expected))
+	writers := make([]io.Writer, len(expected))
+	for i, fh := range expected {
+		hasher, err := hashio.NewHasher(fh.Algorithm)
+		if err != nil {
+			return r, func() error { return err }
+		}
+		hashers[i] = hasher
+		writers[i] = hasher
+	}
+
+	verify := func() error {
+		for i, fh := range expected {
+			hasher := hashers[i]
+			if fh.Size != 0 && hasher.Size() != fh.Size {
+				return fmt.Errorf("%s: invalid size: got %d, want %d",
+					fh.Filename, hasher.Size(), fh.Size)
+			}
+			got := fmt.Sprintf("%x", hasher.Sum(nil))
+			if !strings.EqualFold(got, fh.Hash) {
+				return fmt.Errorf("%s: invalid %s hash: got %s, want %s",
+					fh.Filename, fh.Algorithm, got, fh.Hash)
+			}
+		}
+		return nil
+	}
+
+	return io.TeeReader(r, io.MultiWriter(writers...)), verify
+}
+
 // {{{ Hash File implementations
 
 // ByHashPath returns the corresponding /by-hash/<algorithm>/<hash> path.
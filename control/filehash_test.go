@@ -22,6 +22,7 @@ package control_test
 
 import (
 	"bufio"
+	"io"
 	"strings"
 	"testing"
 
@@ -46,3 +47,59 @@ SHA256:
 		t.Errorf("control.Unmarshal unexpectedly succeeded on struct without delim")
 	}
 }
+
+func TestStreamVerify(t *testing.T) {
+	expected := control.FileHashes{
+		{
+			Algorithm: "md5",
+			Hash:      "5eb63bbbe01eeed093cb22bb8f5acdc3",
+			Size:      11,
+			Filename:  "hello",
+		},
+		{
+			Algorithm: "sha256",
+			Hash:      "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+			Size:      11,
+			Filename:  "hello",
+		},
+	}
+
+	r, verify := control.StreamVerify(strings.NewReader("hello world"), expected)
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); err != nil {
+		t.Errorf("verify: %v", err)
+	}
+
+	r, verify = control.StreamVerify(strings.NewReader("hello World"), expected)
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); err == nil {
+		t.Errorf("verify unexpectedly succeeded on mismatched data")
+	}
+
+	r, verify = control.StreamVerify(strings.NewReader("hello"), expected)
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); err == nil {
+		t.Errorf("verify unexpectedly succeeded on short data")
+	}
+
+	_, verify = control.StreamVerify(strings.NewReader(""), control.FileHashes{
+		{Algorithm: "crc32", Hash: "00"},
+	})
+	if err := verify(); err == nil {
+		t.Errorf("verify unexpectedly succeeded on an unknown algorithm")
+	}
+
+	r, verify = control.StreamVerify(strings.NewReader("hello world"), nil)
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); err == nil {
+		t.Errorf("verify unexpectedly succeeded without any hashes")
+	}
+}
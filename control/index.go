@@ -22,6 +22,8 @@ package control // import "github.com/akozlenkov/go-debian/control"
 
 import (
 	"bufio"
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/akozlenkov/go-debian/dependency"
@@ -108,6 +110,83 @@ func (index *BinaryIndex) SourcePackage() string {
 	return strings.Split(index.Source, " ")[0]
 }
 
+// Check to see if the package is marked as `Essential: yes`, which means
+// dpkg will refuse to remove it without being forced to.
+func (index *BinaryIndex) IsEssential() bool {
+	return index.isYes("Essential")
+}
+
+// Check to see if the package is marked as `Build-Essential: yes`, which
+// means it's always installed when building packages, and need not be
+// listed in Build-Depends.
+func (index *BinaryIndex) IsBuildEssential() bool {
+	return index.isYes("Build-Essential")
+}
+
+func (index *BinaryIndex) isYes(field string) bool {
+	return strings.EqualFold(strings.TrimSpace(index.Values[field]), "yes")
+}
+
+// Return the Version of the package. If the Version member wasn't decoded
+// (as with a BinaryIndex built from a bare Paragraph), the Version field
+// is parsed instead.
+func (index *BinaryIndex) parsedVersion() (version.Version, error) {
+	if !index.Version.Empty() {
+		return index.Version, nil
+	}
+	value, ok := index.Values["Version"]
+	if !ok {
+		return version.Version{}, fmt.Errorf("Package '%s' has no Version", index.Package)
+	}
+	return version.Parse(strings.TrimSpace(value))
+}
+
+// Return the Version of the package without its Debian revision, such as
+// `1:1.2.3` for `1:1.2.3-4`. The epoch is kept, so the returned Version
+// still sorts correctly against other upstream versions.
+func (index *BinaryIndex) UpstreamVersion() (version.Version, error) {
+	ver, err := index.parsedVersion()
+	if err != nil {
+		return version.Version{}, err
+	}
+	ver.Revision = ""
+	return ver, nil
+}
+
+// Return the Debian revision of the package's Version, such as `4` for
+// `1.2.3-4`, or an empty string for a native package.
+func (index *BinaryIndex) DebianRevision() (string, error) {
+	ver, err := index.parsedVersion()
+	if err != nil {
+		return "", err
+	}
+	return ver.DebianRevision(), nil
+}
+
+// Sort the packages in place by Version, oldest first if ascending is set,
+// or newest first otherwise. Packages with equal versions may end up in any
+// order; use StableSortByVersion to keep them in the order they were given.
+func SortByVersion(pkgs []*BinaryIndex, ascending bool) {
+	sort.Slice(pkgs, versionLess(pkgs, ascending))
+}
+
+// Sort the packages in place by Version, just like SortByVersion, but keep
+// packages with equal versions (such as the same version of a package on
+// different architectures) in the order they were given.
+func StableSortByVersion(pkgs []*BinaryIndex, ascending bool) {
+	sort.SliceStable(pkgs, versionLess(pkgs, ascending))
+}
+
+func versionLess(pkgs []*BinaryIndex, ascending bool) func(i, j int) bool {
+	return func(i, j int) bool {
+		cmp := version.Compare(pkgs[i].Version, pkgs[j].Version)
+		if ascending {
+			return cmp < 0
+		}
+		return cmp > 0
+	}
+}
+
 // BestChecksums can be included in a struct instead of e.g. ChecksumsSha256.
 //
 // BestChecksums uses cryptographically secure checksums, so that application
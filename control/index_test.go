@@ -263,4 +263,104 @@ Description-md5: d05e72aec9e53ee776fc0735e135889b
 	assert(t, conflicts[0].Version.Operator == ">=")
 }
 
+func TestSortByVersion(t *testing.T) {
+	newPkgs := func() []*control.BinaryIndex {
+		pkgs := []*control.BinaryIndex{}
+		for _, el := range [][2]string{
+			{"hello", "2.10-3"},
+			{"hello-amd64", "2.10-2"},
+			{"hello", "1:1.0-1"},
+			{"hello-i386", "2.10-2"},
+			{"hello", "2.10~rc1-1"},
+		} {
+			pkg := control.BinaryIndex{Package: el[0]}
+			isok(t, pkg.Version.UnmarshalControl(el[1]))
+			pkgs = append(pkgs, &pkg)
+		}
+		return pkgs
+	}
+
+	pkgs := newPkgs()
+	control.SortByVersion(pkgs, true)
+	versions := []string{}
+	for _, pkg := range pkgs {
+		versions = append(versions, pkg.Version.String())
+	}
+	assert(t, strings.Join(versions, " ") == "2.10~rc1-1 2.10-2 2.10-2 2.10-3 1:1.0-1")
+
+	pkgs = newPkgs()
+	control.StableSortByVersion(pkgs, true)
+	assert(t, pkgs[1].Package == "hello-amd64")
+	assert(t, pkgs[2].Package == "hello-i386")
+
+	pkgs = newPkgs()
+	control.StableSortByVersion(pkgs, false)
+	assert(t, pkgs[0].Version.String() == "1:1.0-1")
+	assert(t, pkgs[2].Package == "hello-amd64")
+	assert(t, pkgs[3].Package == "hello-i386")
+	assert(t, pkgs[4].Version.String() == "2.10~rc1-1")
+}
+
+func TestBinaryIndexIsEssential(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader(`Package: base-files
+Version: 12.4+deb12u5
+Essential: yes
+
+Package: gcc
+Version: 4:12.2.0-3
+Build-Essential: Yes
+
+Package: hello
+Version: 2.10-3
+Essential: no
+`))
+	pkgs, err := control.ParseBinaryIndex(reader)
+	isok(t, err)
+	assert(t, len(pkgs) == 3)
+
+	assert(t, pkgs[0].IsEssential())
+	assert(t, !pkgs[0].IsBuildEssential())
+	assert(t, !pkgs[1].IsEssential())
+	assert(t, pkgs[1].IsBuildEssential())
+	assert(t, !pkgs[2].IsEssential())
+	assert(t, !pkgs[2].IsBuildEssential())
+}
+
+func TestBinaryIndexUpstreamVersion(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader(`Package: hello
+Version: 1:2.10-3+b1
+
+Package: base-files
+Version: 12.4
+`))
+	pkgs, err := control.ParseBinaryIndex(reader)
+	isok(t, err)
+	assert(t, len(pkgs) == 2)
+
+	upstream, err := pkgs[0].UpstreamVersion()
+	isok(t, err)
+	assert(t, upstream.String() == "1:2.10")
+	revision, err := pkgs[0].DebianRevision()
+	isok(t, err)
+	assert(t, revision == "3+b1")
+	assert(t, pkgs[0].Version.String() == "1:2.10-3+b1")
+
+	upstream, err = pkgs[1].UpstreamVersion()
+	isok(t, err)
+	assert(t, upstream.String() == "12.4")
+	revision, err = pkgs[1].DebianRevision()
+	isok(t, err)
+	assert(t, revision == "")
+
+	bare := control.BinaryIndex{Paragraph: control.Paragraph{
+		Values: map[string]string{"Package": "hello", "Version": "2.10-3"},
+	}}
+	upstream, err = bare.UpstreamVersion()
+	isok(t, err)
+	assert(t, upstream.String() == "2.10")
+
+	_, err = (&control.BinaryIndex{}).DebianRevision()
+	notok(t, err)
+}
+
 // vim: foldmethod=marker
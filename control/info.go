@@ -0,0 +1,113 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control // import "github.com/akozlenkov/go-debian/control"
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dpkg info files {{{
+//
+// After a package is installed, dpkg keeps a handful of plain text files
+// about it in `/var/lib/dpkg/info/`, named for the package. These aren't in
+// the control file format, but are parsed here, since they're part of the
+// same installed-package metadata as the dpkg status file.
+
+// Parse a `.md5sums` info file (or the `md5sums` control file of a .deb),
+// which has a line for each file installed by the package, made up of the
+// hex MD5 sum of the file, some whitespace, and the path of the file
+// (relative to the root directory). The returned map is keyed by path, with
+// the hex MD5 sum as the value.
+func ParseInfoMD5Sums(r io.Reader) (map[string]string, error) {
+	ret := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		/* The path may well contain spaces, so only split on the first
+		 * run of whitespace following the hash. */
+		i := strings.IndexAny(line, " \t")
+		if i == -1 {
+			return nil, fmt.Errorf("Bad md5sums line: '%s'", line)
+		}
+		hash, path := line[:i], strings.TrimLeft(line[i:], " \t")
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != 32 || path == "" {
+			return nil, fmt.Errorf("Bad md5sums line: '%s'", line)
+		}
+		ret[path] = hash
+	}
+	return ret, scanner.Err()
+}
+
+// Parse a `.list` info file, which lists every path (including
+// directories) installed by the package, one per line.
+func ParseInfoList(r io.Reader) ([]string, error) {
+	ret := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		ret = append(ret, line)
+	}
+	return ret, scanner.Err()
+}
+
+// Parse a `.conffiles` info file (or the `conffiles` control file of a
+// .deb), which lists the configuration files of the package, one per line,
+// optionally preceded by flags such as `remove-on-upgrade`. The returned map
+// is keyed by path. Since the `.conffiles` file doesn't record the hash of
+// each file, the value is empty, unless the input is in the format of the
+// Conffiles field of the dpkg status file, in which each path is followed
+// by its hex MD5 sum.
+func ParseInfoConffiles(r io.Reader) (map[string]string, error) {
+	ret := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for len(fields) > 0 && !strings.HasPrefix(fields[0], "/") {
+			if fields[0] != "remove-on-upgrade" {
+				return nil, fmt.Errorf("Bad conffiles line: '%s'", scanner.Text())
+			}
+			fields = fields[1:]
+		}
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			ret[fields[0]] = ""
+		default:
+			ret[fields[0]] = fields[1]
+		}
+	}
+	return ret, scanner.Err()
+}
+
+// }}}
+
+// vim: foldmethod=marker
@@ -0,0 +1,78 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+/*
+ *
+ */
+
+func TestParseInfoMD5Sums(t *testing.T) {
+	sums, err := control.ParseInfoMD5Sums(strings.NewReader(`6f3ec6f0b8bd4b6c1a0fa2b2a3e0c2ad  usr/bin/hello
+2b6d0b7f0e34c8ed04bc0fc0a52c3d7b  usr/share/doc/hello/a file with spaces
+`))
+	isok(t, err)
+	assert(t, len(sums) == 2)
+	assert(t, sums["usr/bin/hello"] == "6f3ec6f0b8bd4b6c1a0fa2b2a3e0c2ad")
+	assert(t, sums["usr/share/doc/hello/a file with spaces"] == "2b6d0b7f0e34c8ed04bc0fc0a52c3d7b")
+
+	_, err = control.ParseInfoMD5Sums(strings.NewReader("nothex  usr/bin/hello\n"))
+	notok(t, err)
+}
+
+func TestParseInfoList(t *testing.T) {
+	paths, err := control.ParseInfoList(strings.NewReader(`/.
+/usr
+/usr/bin
+/usr/bin/hello
+
+/usr/share/doc/hello/a file with spaces
+`))
+	isok(t, err)
+	assert(t, len(paths) == 5)
+	assert(t, paths[0] == "/.")
+	assert(t, paths[4] == "/usr/share/doc/hello/a file with spaces")
+}
+
+func TestParseInfoConffiles(t *testing.T) {
+	conffiles, err := control.ParseInfoConffiles(strings.NewReader(`/etc/hello.conf
+remove-on-upgrade /etc/hello.d/old.conf
+ /etc/default/hello 5a8f5e5d3e2c5a0e6b8d0c9e7f4a3b2c obsolete
+`))
+	isok(t, err)
+	assert(t, len(conffiles) == 3)
+	hash, ok := conffiles["/etc/hello.conf"]
+	assert(t, ok && hash == "")
+	_, ok = conffiles["/etc/hello.d/old.conf"]
+	assert(t, ok)
+	assert(t, conffiles["/etc/default/hello"] == "5a8f5e5d3e2c5a0e6b8d0c9e7f4a3b2c")
+
+	_, err = control.ParseInfoConffiles(strings.NewReader("frobnicate /etc/hello.conf\n"))
+	notok(t, err)
+}
+
+// vim: foldmethod=marker
@@ -26,7 +26,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/mail"
+	"path"
+	"sort"
 	"strings"
+	"time"
 	"unicode"
 
 	"golang.org/x/crypto/openpgp"
@@ -55,22 +59,54 @@ func (p *Paragraph) Set(key, value string) {
 	p.Values[key] = value
 }
 
-func (p *Paragraph) WriteTo(out io.Writer) error {
+// Rename the field oldName to newName, keeping its value and its position
+// in the Paragraph. If there's already a field called newName, it's
+// replaced. The return value is false (and the Paragraph is unchanged) if
+// there's no field called oldName.
+func (p *Paragraph) Rename(oldName, newName string) bool {
+	value, found := p.Values[oldName]
+	if !found {
+		return false
+	}
+	if oldName == newName {
+		return true
+	}
+
+	order := make([]string, 0, len(p.Order))
 	for _, key := range p.Order {
-		value := p.Values[key]
+		switch key {
+		case newName:
+			continue
+		case oldName:
+			order = append(order, newName)
+		default:
+			order = append(order, key)
+		}
+	}
+	p.Order = order
 
-		value = strings.Replace(value, "\n", "\n ", -1)
-		value = strings.Replace(value, "\n \n", "\n .\n", -1)
+	delete(p.Values, oldName)
+	p.Values[newName] = value
+	return true
+}
 
-		if _, err := out.Write(
-			[]byte(fmt.Sprintf("%s: %s\n", key, value)),
-		); err != nil {
+func (p *Paragraph) WriteTo(out io.Writer) error {
+	for _, key := range p.Order {
+		if _, err := out.Write([]byte(formatField(key, p.Values[key]))); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// Format a single field as it's written out in a Paragraph, with any
+// continuation lines indented, and blank lines replaced with a ".".
+func formatField(key, value string) string {
+	value = strings.Replace(value, "\n", "\n ", -1)
+	value = strings.Replace(value, "\n \n", "\n .\n", -1)
+	return fmt.Sprintf("%s: %s\n", key, value)
+}
+
 func (p *Paragraph) Update(other Paragraph) Paragraph {
 	ret := Paragraph{
 		Order:  []string{},
@@ -96,6 +132,275 @@ func (p *Paragraph) Update(other Paragraph) Paragraph {
 	return ret
 }
 
+// Check that all of the given field names are present in the Paragraph,
+// returning the names of any which are missing, in the order given. If all
+// of the fields are present, the returned slice is empty.
+func FieldExists(p *Paragraph, names ...string) []string {
+	missing := []string{}
+	for _, name := range names {
+		if _, ok := p.Values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// Return all the Fields of the Paragraph whose names match the given
+// `path.Match` pattern (such as `Checksums-*` or `Vcs-*`), in the order
+// they appear in the Paragraph. A malformed pattern matches nothing.
+func (p *Paragraph) GlobGet(pattern string) []Field {
+	ret := []Field{}
+	for _, key := range p.Order {
+		if ok, err := path.Match(pattern, key); err == nil && ok {
+			ret = append(ret, Field{Name: key, Value: p.Values[key]})
+		}
+	}
+	return ret
+}
+
+// Return a copy of the Paragraph's values as a plain map. This is lossy,
+// since the ordering of the fields is not kept, but it's handy for things
+// like serializing a Paragraph to JSON.
+func (p *Paragraph) ToMap() map[string]string {
+	ret := make(map[string]string, len(p.Values))
+	for key, value := range p.Values {
+		ret[key] = value
+	}
+	return ret
+}
+
+// Create a Paragraph out of a plain map. Since a map has no ordering, the
+// fields of the returned Paragraph are sorted by name, which is stable, but
+// otherwise unlikely to match the ordering of any original Paragraph.
+func ParagraphFromMap(m map[string]string) *Paragraph {
+	ret := Paragraph{
+		Order:  make([]string, 0, len(m)),
+		Values: make(map[string]string, len(m)),
+	}
+	for key, value := range m {
+		ret.Order = append(ret.Order, key)
+		ret.Values[key] = value
+	}
+	sort.Strings(ret.Order)
+	return &ret
+}
+
+// Return a copy of the Paragraph's values as a plain map, suitable for a
+// protobuf `map<string, string>` field. As with ToMap, this loses the
+// ordering of the fields, so if the Paragraph must be rebuilt exactly (for
+// instance, to check a signature over it), the Order has to be sent too,
+// and given to ParagraphFromStringMap.
+func (p *Paragraph) ToStringMap() map[string]string {
+	return p.ToMap()
+}
+
+// Create a Paragraph out of a plain map, such as a protobuf
+// `map<string, string>` field, with the fields in the order given by
+// fieldOrder. Names in fieldOrder which aren't in the map are skipped, and
+// any fields of the map not named in fieldOrder follow the rest, sorted by
+// name, as with ParagraphFromMap.
+func ParagraphFromStringMap(m map[string]string, fieldOrder []string) *Paragraph {
+	ret := Paragraph{
+		Order:  make([]string, 0, len(m)),
+		Values: make(map[string]string, len(m)),
+	}
+	for _, key := range fieldOrder {
+		value, ok := m[key]
+		if _, seen := ret.Values[key]; !ok || seen {
+			continue
+		}
+		ret.Order = append(ret.Order, key)
+		ret.Values[key] = value
+	}
+
+	rest := []string{}
+	for key := range m {
+		if _, ok := ret.Values[key]; !ok {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	for _, key := range rest {
+		ret.Order = append(ret.Order, key)
+		ret.Values[key] = m[key]
+	}
+	return &ret
+}
+
+// Write the Paragraph to the given io.Writer in a human readable form, for
+// debugging. Field names are right aligned so that all the colons line up,
+// and continuation lines of multi-line values are indented to start under
+// the first line of the value. The output is not valid deb822.
+func (p *Paragraph) PrettyPrint(w io.Writer) error {
+	return p.PrettyPrintColumn(w, 0)
+}
+
+// Like PrettyPrint, but align the colons at the given column. If the column
+// is too narrow to fit the longest field name (including when it's 0), the
+// colons are aligned just after the longest field name instead.
+func (p *Paragraph) PrettyPrintColumn(w io.Writer, column int) error {
+	for _, key := range p.Order {
+		if len(key) > column {
+			column = len(key)
+		}
+	}
+	indent := "\n" + strings.Repeat(" ", column+2)
+
+	for _, key := range p.Order {
+		value := strings.Replace(p.Values[key], "\n", indent, -1)
+		if _, err := fmt.Fprintf(w, "%*s: %s\n", column, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Return the Paragraph as a human readable string, as written by
+// PrettyPrint. This is deliberately not called String, since Paragraph is
+// embedded in most of the types of this package, and they would all become
+// a fmt.Stringer.
+func (p *Paragraph) PrettyString() string {
+	var buf bytes.Buffer
+	p.PrettyPrint(&buf)
+	return buf.String()
+}
+
+// }}}
+
+// List Helpers {{{
+
+// FieldSeparator maps the names of well known list fields to the separator
+// used between their elements. Relationship fields and the like are comma
+// separated, while fields such as Architecture are whitespace separated.
+//
+// The Binary field is absent, since it's comma separated in a .dsc, yet
+// whitespace separated in a .changes.
+var FieldSeparator = map[string]string{
+	"Architecture":          " ",
+	"Architectures":         " ",
+	"Build-Conflicts":       ",",
+	"Build-Conflicts-Arch":  ",",
+	"Build-Conflicts-Indep": ",",
+	"Build-Depends":         ",",
+	"Build-Depends-Arch":    ",",
+	"Build-Depends-Indep":   ",",
+	"Breaks":                ",",
+	"Built-Using":           ",",
+	"Closes":                " ",
+	"Components":            " ",
+	"Conflicts":             ",",
+	"Depends":               ",",
+	"Enhances":              ",",
+	"Pre-Depends":           ",",
+	"Provides":              ",",
+	"Recommends":            ",",
+	"Replaces":              ",",
+	"Static-Built-Using":    ",",
+	"Suggests":              ",",
+	"Tag":                   ",",
+	"Testsuite":             ",",
+	"Uploaders":             ",",
+}
+
+// Return the value of the named field split on whitespace. If the field
+// is not set, the returned slice is empty.
+func (p *Paragraph) GetSpaceList(name string) []string {
+	return strings.Fields(p.Values[name])
+}
+
+// Set the named field to the given values, separated by a single space.
+func (p *Paragraph) SetSpaceList(name string, values []string) {
+	p.Set(name, strings.Join(values, " "))
+}
+
+// Return the value of the named field split on commas, with any whitespace
+// (including newlines) around each element removed, and empty elements
+// dropped. If the field is not set, the returned slice is empty.
+func (p *Paragraph) GetCommaList(name string) []string {
+	ret := []string{}
+	for _, el := range strings.Split(p.Values[name], ",") {
+		if el = strings.TrimSpace(el); el != "" {
+			ret = append(ret, el)
+		}
+	}
+	return ret
+}
+
+// Set the named field to the given values, separated by `, `.
+func (p *Paragraph) SetCommaList(name string, values []string) {
+	p.Set(name, strings.Join(values, ", "))
+}
+
+// Return the value of the named field as a list, using the separator
+// given for the field in FieldSeparator. Fields not in FieldSeparator
+// are split on whitespace.
+func (p *Paragraph) GetList(name string) []string {
+	if FieldSeparator[name] == "," {
+		return p.GetCommaList(name)
+	}
+	return p.GetSpaceList(name)
+}
+
+// Set the named field to the given values, using the separator given for
+// the field in FieldSeparator. Fields not in FieldSeparator are separated
+// with a single space.
+func (p *Paragraph) SetList(name string, values []string) {
+	if FieldSeparator[name] == "," {
+		p.SetCommaList(name, values)
+		return
+	}
+	p.SetSpaceList(name, values)
+}
+
+// }}}
+
+// Timestamp Helpers {{{
+
+// The layout used for dates written by SetTimestamp, which is the RFC 2822
+// date format with a numeric timezone, as written by `date -R`.
+const timestampLayout = time.RFC1123Z
+
+// Layouts tried by GetTimestamp after mail.ParseDate fails, to cope with
+// dates written by less careful tools.
+var timestampFallbackLayouts = []string{
+	time.RFC1123,
+	time.UnixDate,
+	time.RFC3339,
+}
+
+// Set the named field to the given time, formatted as an RFC 2822 date
+// with a numeric timezone, such as `Sat, 10 Aug 2024 20:04:42 +0000`, as
+// used in the Date field of a Release or .changes file.
+func (p *Paragraph) SetTimestamp(name string, t time.Time) error {
+	if t.IsZero() {
+		return fmt.Errorf("Refusing to set '%s' to the zero time", name)
+	}
+	p.Set(name, t.Format(timestampLayout))
+	return nil
+}
+
+// Parse the named field as a date, such as the Date field of a Release or
+// .changes file. This accepts RFC 2822 dates (with either a numeric or a
+// named timezone, such as `UTC`), as well as a handful of other common
+// formats written by tools which get this wrong.
+func (p *Paragraph) GetTimestamp(name string) (time.Time, error) {
+	value, ok := p.Values[name]
+	if !ok {
+		return time.Time{}, fmt.Errorf("No such field '%s'", name)
+	}
+	value = strings.TrimSpace(value)
+
+	if t, err := mail.ParseDate(value); err == nil {
+		return t, nil
+	}
+	for _, layout := range timestampFallbackLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Unable to parse '%s' as a date: '%s'", name, value)
+}
+
 // }}}
 
 // ParagraphReader {{{
@@ -109,6 +414,33 @@ func (p *Paragraph) Update(other Paragraph) Paragraph {
 type ParagraphReader struct {
 	reader *bufio.Reader
 	signer *openpgp.Entity
+
+	/* Scratch space for Next, to avoid allocating for every Paragraph,
+	 * or every line. */
+	line      []byte
+	keys      map[string]string
+	fieldHint int
+
+	/* The position of the parser, as returned by Position. */
+	lineNumber int
+	column     int
+}
+
+// A ParseError is returned by ParagraphReader.Next when the input can't be
+// parsed, giving the Line and Col at which the problem was found, as
+// returned by ParagraphReader.Position.
+type ParseError struct {
+	Line int
+	Col  int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Col, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
 }
 
 // {{{ NewParagraphReader
@@ -151,6 +483,21 @@ func (p *ParagraphReader) Signer() *openpgp.Entity {
 
 // }}}
 
+// Position {{{
+
+// Return the position of the parser in the input: the line number of the
+// last line read, and the column in it (counted in bytes), both starting at
+// 1. The column is 1 unless Next failed on the line, in which case it's
+// where the fault was found, such as where the ':' of a field is missing.
+// If nothing has been read yet, this is (0, 0). For a clearsigned document,
+// the position is within the signed text, not counting the OpenPGP armor
+// lines.
+func (p *ParagraphReader) Position() (line, col int) {
+	return p.lineNumber, p.column
+}
+
+// }}}
+
 // All {{{
 
 func (p *ParagraphReader) All() ([]Paragraph, error) {
@@ -171,25 +518,51 @@ func (p *ParagraphReader) All() ([]Paragraph, error) {
 // Next {{{
 
 // Consume the io.Reader and return the next parsed Paragraph, modulo
-// garbage lines causing us to return an error.
+// garbage lines causing us to return an error. Any error other than
+// io.EOF is a *ParseError, giving the position the error was found at.
 func (p *ParagraphReader) Next() (*Paragraph, error) {
+	paragraph, err := p.next()
+	if err != nil && err != io.EOF {
+		return nil, &ParseError{Line: p.lineNumber, Col: p.column, Err: err}
+	}
+	return paragraph, err
+}
+
+func (p *ParagraphReader) next() (*Paragraph, error) {
 	paragraph := Paragraph{
-		Order:  []string{},
-		Values: map[string]string{},
+		Order:  make([]string, 0, p.fieldHint),
+		Values: make(map[string]string, p.fieldHint),
 	}
 	var lastKey string
 
+	/* Continuation lines are gathered up in value, and only written back
+	 * to the Paragraph once the field is over, rather than growing the
+	 * string in the map one line at a time. */
+	var value strings.Builder
+	building := false
+	flush := func() {
+		if building {
+			paragraph.Values[lastKey] = value.String()
+			building = false
+		}
+	}
+	done := func() (*Paragraph, error) {
+		flush()
+		p.fieldHint = len(paragraph.Order)
+		return &paragraph, nil
+	}
+
 	for {
-		line, err := p.reader.ReadString('\n')
-		if err == io.EOF && line != "" {
+		line, err := p.readLine()
+		if err == io.EOF && len(line) != 0 {
 			err = nil
-			line = line + "\n"
+			line = append(line, '\n')
 			/* We'll clean up the last of the buffer. */
 		}
 		if err == io.EOF {
 			/* Let's return the parsed paragraph if we have it */
 			if len(paragraph.Order) > 0 {
-				return &paragraph, nil
+				return done()
 			}
 			/* Else, let's go ahead and drop the EOF out raw */
 			return nil, err
@@ -197,17 +570,17 @@ func (p *ParagraphReader) Next() (*Paragraph, error) {
 			return nil, err
 		}
 
-		if line == "\n" || line == "\r\n" {
+		if (len(line) == 1 && line[0] == '\n') || (len(line) == 2 && line[0] == '\r' && line[1] == '\n') {
 			if len(paragraph.Order) == 0 {
 				/* Skip over any number of blank lines between paragraphs. */
 				continue
 			}
 			/* Lines are ended by a blank line; so we're able to go ahead
 			 * and return this guy as-is. All set. Done. Finished. */
-			return &paragraph, nil
+			return done()
 		}
 
-		if strings.HasPrefix(line, "#") {
+		if line[0] == '#' {
 			continue // skip comments
 		}
 
@@ -220,7 +593,7 @@ func (p *ParagraphReader) Next() (*Paragraph, error) {
 		 * Key line is a Key/Value mapping.
 		 */
 
-		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+		if line[0] == ' ' || line[0] == '\t' {
 			/* This is a continuation line; so we're going to go ahead and
 			 * clean it up, and throw it into the list. We're going to remove
 			 * the first character (which we now know is whitespace), and if
@@ -228,40 +601,88 @@ func (p *ParagraphReader) Next() (*Paragraph, error) {
 			 * (since " .\n" is actually "\n"). We only trim off space on the
 			 * right hand, because indentation under the whitespace is up to
 			 * the data format. Not us. */
+			line = bytes.TrimRightFunc(line[1:], unicode.IsSpace)
 
-			/* TrimFunc(line[1:], unicode.IsSpace) is identical to calling
-			 * TrimSpace. */
-			line = strings.TrimRightFunc(line[1:], unicode.IsSpace)
-
-			if line == "." {
-				line = ""
+			if len(line) == 1 && line[0] == '.' {
+				line = line[:0]
 			}
 
-			if paragraph.Values[lastKey] == "" {
-				paragraph.Values[lastKey] = line + "\n"
-			} else {
-				if !strings.HasSuffix(paragraph.Values[lastKey], "\n") {
-					paragraph.Values[lastKey] = paragraph.Values[lastKey] + "\n"
+			if !building {
+				/* The first continuation line; start off with the value
+				 * from the Key line, if any. */
+				value.Reset()
+				if existing := paragraph.Values[lastKey]; existing != "" {
+					value.WriteString(existing)
+					if !strings.HasSuffix(existing, "\n") {
+						value.WriteByte('\n')
+					}
 				}
-				paragraph.Values[lastKey] = paragraph.Values[lastKey] + line + "\n"
+				building = true
 			}
+			value.Write(line)
+			value.WriteByte('\n')
 			continue
 		}
 
 		/* So, if we're here, we've got a key line. Let's go ahead and split
 		 * this on the first key, and set that guy */
-		els := strings.SplitN(line, ":", 2)
-		if len(els) != 2 {
+		colon := bytes.IndexByte(line, ':')
+		if colon == -1 {
+			/* The ':' should have been at the end of the field name,
+			 * which can't contain any whitespace. */
+			line = bytes.TrimRightFunc(line, unicode.IsSpace)
+			if end := bytes.IndexAny(line, " \t"); end != -1 {
+				p.column = end + 1
+			} else {
+				p.column = len(line) + 1
+			}
 			return nil, fmt.Errorf("Bad line: '%s' has no ':'", line)
 		}
 
+		flush()
+
 		/* We'll go ahead and take off any leading spaces */
-		lastKey = strings.TrimSpace(els[0])
-		value := strings.TrimSpace(els[1])
+		lastKey = p.intern(bytes.TrimSpace(line[:colon]))
 
 		paragraph.Order = append(paragraph.Order, lastKey)
-		paragraph.Values[lastKey] = value
+		paragraph.Values[lastKey] = string(bytes.TrimSpace(line[colon+1:]))
+	}
+}
+
+// Read the next line, including the trailing newline (if there is one).
+// The returned slice is only valid until the next call to readLine.
+func (p *ParagraphReader) readLine() ([]byte, error) {
+	line, err := p.reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		/* The line is longer than the bufio.Reader's buffer; so we'll
+		 * have to gather it up ourselves. */
+		p.line = append(p.line[:0], line...)
+		for err == bufio.ErrBufferFull {
+			line, err = p.reader.ReadSlice('\n')
+			p.line = append(p.line, line...)
+		}
+		line = p.line
+	}
+
+	if len(line) != 0 {
+		p.lineNumber++
+		p.column = 1
+	}
+	return line, err
+}
+
+// Return the field name as a string, reusing the same string for every
+// Paragraph, since the same few field names are repeated over and over.
+func (p *ParagraphReader) intern(key []byte) string {
+	if name, ok := p.keys[string(key)]; ok {
+		return name
+	}
+	if p.keys == nil {
+		p.keys = map[string]string{}
 	}
+	name := string(key)
+	p.keys[name] = name
+	return name
 }
 
 // }}}
@@ -21,10 +21,14 @@
 package control_test
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/akozlenkov/go-debian/control"
 	"golang.org/x/crypto/openpgp"
@@ -176,6 +180,118 @@ func TestParagraphSet(t *testing.T) {
 	assert(t, para.Values["british"] == "redcoat")
 }
 
+func TestParagraphMap(t *testing.T) {
+	para := control.Paragraph{
+		Order:  []string{"Source", "Binary"},
+		Values: map[string]string{"Source": "hy", "Binary": "hy python3-hy"},
+	}
+	values := para.ToMap()
+	assert(t, len(values) == 2)
+	assert(t, values["Source"] == "hy")
+
+	/* The map is a copy, and must not alias the Paragraph */
+	values["Source"] = "fbautostart"
+	assert(t, para.Values["Source"] == "hy")
+
+	newPara := control.ParagraphFromMap(values)
+	assert(t, len(newPara.Order) == 2)
+	assert(t, newPara.Order[0] == "Binary")
+	assert(t, newPara.Order[1] == "Source")
+	assert(t, newPara.Values["Source"] == "fbautostart")
+}
+
+func TestParagraphStringMap(t *testing.T) {
+	para := control.Paragraph{
+		Order:  []string{"Source", "Binary", "Architecture"},
+		Values: map[string]string{"Source": "hy", "Binary": "hy python3-hy", "Architecture": "all"},
+	}
+	values := para.ToStringMap()
+	assert(t, len(values) == 3)
+	values["Source"] = "fbautostart"
+	assert(t, para.Values["Source"] == "hy")
+
+	newPara := control.ParagraphFromStringMap(values, para.Order)
+	assert(t, strings.Join(newPara.Order, " ") == "Source Binary Architecture")
+	assert(t, newPara.Values["Source"] == "fbautostart")
+
+	newPara = control.ParagraphFromStringMap(values, []string{"Binary", "Version", "Binary"})
+	assert(t, strings.Join(newPara.Order, " ") == "Binary Architecture Source")
+	assert(t, len(newPara.Values) == 3)
+}
+
+func TestFieldExists(t *testing.T) {
+	para := control.Paragraph{
+		Order:  []string{"Package", "Version"},
+		Values: map[string]string{"Package": "hy", "Version": "0.11.0-4"},
+	}
+	assert(t, len(control.FieldExists(&para, "Package", "Version")) == 0)
+
+	missing := control.FieldExists(&para, "Architecture", "Package", "Maintainer")
+	assert(t, len(missing) == 2)
+	assert(t, missing[0] == "Architecture")
+	assert(t, missing[1] == "Maintainer")
+}
+
+func TestParagraphLists(t *testing.T) {
+	reader, err := control.NewParagraphReader(strings.NewReader(`Package: hello
+Architecture: amd64  arm64
+Depends: libc6 (>= 2.14),
+ dpkg (>= 1.15.4) | install-info,
+`), nil)
+	isok(t, err)
+	paragraph, err := reader.Next()
+	isok(t, err)
+
+	arches := paragraph.GetSpaceList("Architecture")
+	assert(t, len(arches) == 2)
+	assert(t, arches[1] == "arm64")
+
+	depends := paragraph.GetCommaList("Depends")
+	assert(t, len(depends) == 2)
+	assert(t, depends[1] == "dpkg (>= 1.15.4) | install-info")
+	assert(t, len(paragraph.GetList("Depends")) == 2)
+	assert(t, len(paragraph.GetList("Architecture")) == 2)
+	assert(t, len(paragraph.GetCommaList("Recommends")) == 0)
+
+	paragraph.SetList("Architecture", []string{"amd64", "i386"})
+	assert(t, paragraph.Values["Architecture"] == "amd64 i386")
+	paragraph.SetList("Recommends", []string{"foo", "bar"})
+	assert(t, paragraph.Values["Recommends"] == "foo, bar")
+	assert(t, paragraph.Order[len(paragraph.Order)-1] == "Recommends")
+	paragraph.SetSpaceList("Closes", []string{"1234", "5678"})
+	assert(t, paragraph.Values["Closes"] == "1234 5678")
+	paragraph.SetCommaList("Tag", []string{"devel::lang:c", "role::program"})
+	assert(t, len(paragraph.GetCommaList("Tag")) == 2)
+}
+
+func TestParagraphGlobGet(t *testing.T) {
+	reader, err := control.NewParagraphReader(strings.NewReader(`Source: hello
+Vcs-Git: https://salsa.debian.org/sanvila/hello.git
+Checksums-Sha1:
+ f7bebf6f9c62a2295e889f66e05ce9bfaed9ace3 725946 hello_2.10.orig.tar.gz
+Vcs-Browser: https://salsa.debian.org/sanvila/hello
+Checksums-Sha256:
+ 31e066137a962676e89f69d1b65382de95a7ef7d914b8cb956f41ea72e0f516b 725946 hello_2.10.orig.tar.gz
+`), nil)
+	isok(t, err)
+	paragraph, err := reader.Next()
+	isok(t, err)
+
+	vcs := paragraph.GlobGet("Vcs-*")
+	assert(t, len(vcs) == 2)
+	assert(t, vcs[0] == control.Field{Name: "Vcs-Git", Value: "https://salsa.debian.org/sanvila/hello.git"})
+	assert(t, vcs[1].Name == "Vcs-Browser")
+
+	checksums := paragraph.GlobGet("Checksums-*")
+	assert(t, len(checksums) == 2)
+	assert(t, checksums[1].Name == "Checksums-Sha256")
+	assert(t, strings.Contains(checksums[1].Value, "hello_2.10.orig.tar.gz"))
+
+	assert(t, len(paragraph.GlobGet("*")) == 5)
+	assert(t, len(paragraph.GlobGet("Files")) == 0)
+	assert(t, len(paragraph.GlobGet("[")) == 0)
+}
+
 func TestWhitespacePrefixedLines(t *testing.T) {
 	// Reader {{{
 	reader, err := control.NewParagraphReader(strings.NewReader(`Key1: one
@@ -313,4 +429,162 @@ func TestLineWrapping(t *testing.T) {
 `)
 }
 
+func TestParagraphPrettyPrint(t *testing.T) {
+	para := control.Paragraph{
+		Order: []string{"Source", "Version", "Description"},
+		Values: map[string]string{
+			"Source":      "hello",
+			"Version":     "2.10-3",
+			"Description": "example package\nbased on GNU hello",
+		},
+	}
+
+	assert(t, para.PrettyString() == `     Source: hello
+    Version: 2.10-3
+Description: example package
+             based on GNU hello
+`)
+
+	buf := bytes.Buffer{}
+	isok(t, para.PrettyPrintColumn(&buf, 13))
+	assert(t, buf.String() == `       Source: hello
+      Version: 2.10-3
+  Description: example package
+               based on GNU hello
+`)
+
+	buf.Reset()
+	isok(t, para.PrettyPrintColumn(&buf, 2))
+	assert(t, buf.String() == para.PrettyString())
+
+	// Paragraph is embedded all over, so it mustn't turn them into Stringers.
+	_, ok := interface{}(&control.BinaryIndex{}).(fmt.Stringer)
+	assert(t, !ok)
+}
+
+func TestParagraphRename(t *testing.T) {
+	para := control.Paragraph{
+		Order: []string{"Package", "Recommended", "Suggests"},
+		Values: map[string]string{
+			"Package":     "hello",
+			"Recommended": "cowsay",
+			"Suggests":    "fortune",
+		},
+	}
+
+	assert(t, para.Rename("Recommended", "Recommends"))
+	assert(t, strings.Join(para.Order, " ") == "Package Recommends Suggests")
+	assert(t, para.Values["Recommends"] == "cowsay")
+	_, found := para.Values["Recommended"]
+	assert(t, !found)
+
+	assert(t, !para.Rename("Recommended", "Recommends"))
+	assert(t, para.Rename("Suggests", "Suggests"))
+	assert(t, len(para.Order) == 3)
+
+	assert(t, para.Rename("Suggests", "Package"))
+	assert(t, strings.Join(para.Order, " ") == "Recommends Package")
+	assert(t, para.Values["Package"] == "fortune")
+	assert(t, len(para.Values) == 2)
+}
+
+func TestParagraphReaderEdgeCases(t *testing.T) {
+	long := strings.Repeat("x", 10000)
+	reader, err := control.NewParagraphReader(strings.NewReader("Files:\n a 1 a.dsc\n .\n b 2 b.tar.xz\n"+
+		"Description: short\n long\r\n# a comment\n"+
+		"Description: replaced\n again\n"+
+		"Long: "+long+"\n continued "+long+"\n"+
+		"Empty:\n\r\n\n\n"+
+		"Last: no newline"), nil)
+	isok(t, err)
+
+	para, err := reader.Next()
+	isok(t, err)
+	assert(t, strings.Join(para.Order, " ") == "Files Description Description Long Empty")
+	assert(t, para.Values["Files"] == "a 1 a.dsc\n\nb 2 b.tar.xz\n")
+	assert(t, para.Values["Description"] == "replaced\nagain\n")
+	assert(t, para.Values["Long"] == long+"\ncontinued "+long+"\n")
+	assert(t, para.Values["Empty"] == "")
+
+	para, err = reader.Next()
+	isok(t, err)
+	assert(t, len(para.Order) == 1)
+	assert(t, para.Values["Last"] == "no newline")
+
+	_, err = reader.Next()
+	assert(t, err == io.EOF)
+
+	reader, err = control.NewParagraphReader(strings.NewReader("Package: hello\nno colon here\n"), nil)
+	isok(t, err)
+	_, err = reader.Next()
+	notok(t, err)
+}
+
+func TestParagraphTimestamp(t *testing.T) {
+	paragraph := control.Paragraph{Values: map[string]string{}, Order: []string{}}
+
+	when := time.Date(2024, 8, 10, 20, 4, 42, 0, time.FixedZone("", 2*60*60))
+	isok(t, paragraph.SetTimestamp("Date", when))
+	assert(t, paragraph.Values["Date"] == "Sat, 10 Aug 2024 20:04:42 +0200")
+	parsed, err := paragraph.GetTimestamp("Date")
+	isok(t, err)
+	assert(t, parsed.Equal(when))
+
+	notok(t, paragraph.SetTimestamp("Date", time.Time{}))
+
+	for _, value := range []string{
+		"Sat, 10 Aug 2024 18:04:42 UTC",
+		"Sat, 10 Aug 2024 18:04:42 +0000",
+		"10 Aug 2024 18:04:42 -0000",
+		"Sat, 10 Aug 2024 18:04:42 GMT",
+		"Sat Aug 10 18:04:42 UTC 2024",
+		"2024-08-10T18:04:42Z",
+	} {
+		paragraph.Set("Date", value)
+		parsed, err := paragraph.GetTimestamp("Date")
+		isok(t, err)
+		assert(t, parsed.Equal(when))
+	}
+
+	paragraph.Set("Date", "last tuesday")
+	_, err = paragraph.GetTimestamp("Date")
+	notok(t, err)
+	_, err = paragraph.GetTimestamp("Valid-Until")
+	notok(t, err)
+}
+
+func TestParagraphReaderPosition(t *testing.T) {
+	reader, err := control.NewParagraphReader(strings.NewReader(`Package: hello
+Description: greeting
+ friendly
+
+Package: hello-doc
+this line is broken
+`), nil)
+	isok(t, err)
+	line, col := reader.Position()
+	assert(t, line == 0 && col == 0)
+
+	_, err = reader.Next()
+	isok(t, err)
+	line, col = reader.Position()
+	assert(t, line == 4 && col == 1)
+
+	_, err = reader.Next()
+	notok(t, err)
+	parseErr := &control.ParseError{}
+	assert(t, errors.As(err, &parseErr))
+	assert(t, parseErr.Line == 6)
+	assert(t, parseErr.Col == 5)
+	assert(t, strings.HasPrefix(err.Error(), "6:5: Bad line: "))
+	line, col = reader.Position()
+	assert(t, line == 6 && col == 5)
+
+	reader, err = control.NewParagraphReader(strings.NewReader("Package: hello\nVersion\r\n"), nil)
+	isok(t, err)
+	_, err = reader.Next()
+	assert(t, errors.As(err, &parseErr))
+	assert(t, parseErr.Line == 2 && parseErr.Col == 8)
+}
+
 // vim: foldmethod=marker
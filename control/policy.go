@@ -0,0 +1,146 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control // import "github.com/akozlenkov/go-debian/control"
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/akozlenkov/go-debian/dependency"
+	"github.com/akozlenkov/go-debian/version"
+)
+
+// A PolicyViolation describes a way in which a control stanza doesn't
+// follow Debian policy, naming the Field at fault, a short identifier for
+// the Rule which was broken (such as `required` or `package-name`), and a
+// human readable Message.
+type PolicyViolation struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("%s: %s (%s)", v.Field, v.Message, v.Rule)
+}
+
+// The fields every binary package stanza must have.
+var binaryRequiredFields = []string{
+	"Package",
+	"Version",
+	"Architecture",
+	"Maintainer",
+	"Description",
+	"Installed-Size",
+}
+
+// Debian policy §5.6.1: at least two characters, lowercase letters, digits
+// and `+-.`, starting with an alphanumeric character.
+var packageNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9.+-]+$`)
+
+// Check the binary package stanza against Debian policy, returning the
+// list of every violation found, or an empty list if the stanza is valid.
+// The checks are made against the fields as they were in the stanza, so
+// they also work on a BinaryIndex built from a bare Paragraph which would
+// fail to decode.
+func (index *BinaryIndex) Validate() []PolicyViolation {
+	ret := []PolicyViolation{}
+	fields := map[string]string{}
+
+	for _, field := range binaryRequiredFields {
+		value := strings.TrimSpace(index.Values[field])
+		if value == "" {
+			ret = append(ret, PolicyViolation{
+				Field:   field,
+				Rule:    "required",
+				Message: "Required field is missing",
+			})
+			continue
+		}
+		fields[field] = value
+	}
+
+	if name, ok := fields["Package"]; ok && !packageNameRegexp.MatchString(name) {
+		ret = append(ret, PolicyViolation{
+			Field:   "Package",
+			Rule:    "package-name",
+			Message: fmt.Sprintf("Invalid package name '%s'", name),
+		})
+	}
+
+	if value, ok := fields["Version"]; ok {
+		if _, err := version.Parse(value); err != nil {
+			ret = append(ret, PolicyViolation{
+				Field:   "Version",
+				Rule:    "version",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if value, ok := fields["Architecture"]; ok {
+		arch, err := dependency.ParseArch(value)
+		if err != nil || !arch.IsKnown() {
+			ret = append(ret, PolicyViolation{
+				Field:   "Architecture",
+				Rule:    "architecture",
+				Message: fmt.Sprintf("Unknown architecture '%s'", value),
+			})
+		}
+	}
+
+	if value, ok := fields["Maintainer"]; ok {
+		if _, _, err := ParseEmailAddress(value); err != nil {
+			ret = append(ret, PolicyViolation{
+				Field:   "Maintainer",
+				Rule:    "maintainer",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if _, ok := fields["Description"]; ok {
+		synopsis := strings.SplitN(index.Values["Description"], "\n", 2)[0]
+		if strings.TrimSpace(synopsis) == "" {
+			ret = append(ret, PolicyViolation{
+				Field:   "Description",
+				Rule:    "synopsis",
+				Message: "Description has an empty synopsis line",
+			})
+		}
+	}
+
+	if value, ok := fields["Installed-Size"]; ok {
+		if size, err := strconv.Atoi(value); err != nil || size < 0 {
+			ret = append(ret, PolicyViolation{
+				Field:   "Installed-Size",
+				Rule:    "installed-size",
+				Message: fmt.Sprintf("Invalid size '%s'", value),
+			})
+		}
+	}
+
+	return ret
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,96 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control_test
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+/*
+ *
+ */
+
+func violatedRules(violations []control.PolicyViolation) []string {
+	ret := []string{}
+	for _, violation := range violations {
+		ret = append(ret, violation.Field+"/"+violation.Rule)
+	}
+	return ret
+}
+
+func TestBinaryIndexValidate(t *testing.T) {
+	// Test Packages {{{
+	reader := bufio.NewReader(strings.NewReader(`Package: hello
+Version: 2.10-3
+Installed-Size: 280
+Maintainer: Santiago Vila <sanvila@debian.org>
+Architecture: amd64
+Description: example package based on GNU hello
+ The GNU hello program produces a familiar, friendly greeting.
+
+Package: Hello_World
+Version: a:b
+Installed-Size: big
+Maintainer: Santiago Vila
+Architecture: z80
+Description: hello
+
+Package: x
+Version: 1.0
+Architecture: linux-any
+`))
+	// }}}
+	// ParseBinaryIndex would reject the broken stanzas outright, so build
+	// the BinaryIndex values from the bare Paragraphs.
+	paragraphs, err := control.NewParagraphReader(reader, nil)
+	isok(t, err)
+	pkgs := []control.BinaryIndex{}
+	for {
+		para, err := paragraphs.Next()
+		if err == io.EOF {
+			break
+		}
+		isok(t, err)
+		pkgs = append(pkgs, control.BinaryIndex{Paragraph: *para})
+	}
+	assert(t, len(pkgs) == 3)
+
+	assert(t, len(pkgs[0].Validate()) == 0)
+
+	assert(t, strings.Join(violatedRules(pkgs[1].Validate()), " ") == "Package/package-name "+
+		"Version/version Architecture/architecture Maintainer/maintainer Installed-Size/installed-size")
+
+	assert(t, strings.Join(violatedRules(pkgs[2].Validate()), " ") == "Maintainer/required "+
+		"Description/required Installed-Size/required Package/package-name Architecture/architecture")
+
+	pkgs[0].Values["Description"] = " \n long description\n"
+	violations := pkgs[0].Validate()
+	assert(t, len(violations) == 1)
+	assert(t, violations[0].Rule == "synopsis")
+	assert(t, violations[0].String() == "Description: Description has an empty synopsis line (synopsis)")
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,81 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control // import "github.com/akozlenkov/go-debian/control"
+
+import (
+	"io"
+	"strings"
+)
+
+// A Task is a single task definition, as used by tasksel(8), and found in
+// the files under /usr/share/tasksel/descs/, such as "web server" or
+// "standard system utilities".
+type Task struct {
+	Paragraph
+
+	Task           string `required:"true"`
+	Parent         string
+	Section        string
+	Relevance      int
+	Description    string
+	Key            []string `delim:"\n" strip:"\n\r\t "`
+	Packages       string
+	TestNewInstall string `control:"Test-new-install"`
+	Enhances       string
+}
+
+// Parse all of the task definitions from a tasksel .desc file.
+func ParseTaskFile(r io.Reader) ([]*Task, error) {
+	tasks := []Task{}
+	if err := Unmarshal(&tasks, r); err != nil {
+		return nil, err
+	}
+	ret := make([]*Task, len(tasks))
+	for i := range tasks {
+		ret[i] = &tasks[i]
+	}
+	return ret, nil
+}
+
+// The ways tasksel can select the packages of a task, one of which may be
+// given at the start of the Packages field.
+var taskPackagesMethods = map[string]bool{
+	"list":        true,
+	"manual":      true,
+	"standard":    true,
+	"task-fields": true,
+}
+
+// Return the package names listed in the Packages field of the Task, which
+// may be separated by commas, whitespace or newlines. If the field starts
+// with the selection method (such as `list`), that's skipped, so for any
+// method other than `list` this is usually empty.
+func (t *Task) PackageList() []string {
+	words := strings.FieldsFunc(t.Packages, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	if len(words) != 0 && taskPackagesMethods[words[0]] {
+		words = words[1:]
+	}
+	return words
+}
+
+// vim: foldmethod=marker
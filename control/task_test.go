@@ -0,0 +1,81 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+/*
+ *
+ */
+
+// Task file {{{
+const taskFile = `Task: web-server
+Relevance: 5
+Section: server
+Description: web server
+ This task selects packages useful for a general purpose web server
+ system.
+Key:
+  apache2
+Packages: list
+  apache2,
+  apache2-doc
+  libapache2-mod-php
+Test-new-install: skip show
+
+Task: standard
+Relevance: 3
+Section: user
+Description: standard system utilities
+ This task sets up a basic user environment.
+Packages: standard
+`
+
+// }}}
+
+func TestParseTaskFile(t *testing.T) {
+	tasks, err := control.ParseTaskFile(strings.NewReader(taskFile))
+	isok(t, err)
+	assert(t, len(tasks) == 2)
+
+	webServer := tasks[0]
+	assert(t, webServer.Task == "web-server")
+	assert(t, webServer.Relevance == 5)
+	assert(t, webServer.Section == "server")
+	assert(t, strings.HasPrefix(webServer.Description, "web server\n"))
+	assert(t, len(webServer.Key) == 1)
+	assert(t, webServer.Key[0] == "apache2")
+	assert(t, webServer.TestNewInstall == "skip show")
+	assert(t, strings.Join(webServer.PackageList(), " ") == "apache2 apache2-doc libapache2-mod-php")
+
+	assert(t, tasks[1].Task == "standard")
+	assert(t, len(tasks[1].PackageList()) == 0)
+
+	_, err = control.ParseTaskFile(strings.NewReader("Section: server\n"))
+	notok(t, err)
+}
+
+// vim: foldmethod=marker
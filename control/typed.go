@@ -0,0 +1,200 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control // import "github.com/akozlenkov/go-debian/control"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// TypedWriter {{{
+
+// A TypedWriter writes values of a single struct type T out in the Debian
+// control-file format, following the same rules (and struct tags) as the
+// Encoder. Rather than looking at the struct tags on every call, as the
+// Encoder does, the layout of T is worked out once, and cached for every
+// TypedWriter of that type, which makes the TypedWriter a better fit for
+// writing out very large indices, such as a Packages file.
+//
+// A TypedWriter is not safe for concurrent use.
+type TypedWriter[T any] struct {
+	writer         io.Writer
+	encoder        *typedEncoder
+	err            error
+	alreadyWritten bool
+	buf            bytes.Buffer
+}
+
+// Create a new TypedWriter, which writes values of type T to the given
+// io.Writer. T must be a struct, or a pointer to a struct; if it's not,
+// every call to Write returns an error.
+func NewTypedWriter[T any](w io.Writer) *TypedWriter[T] {
+	encoder, err := typedEncoderFor(reflect.TypeOf((*T)(nil)).Elem())
+	return &TypedWriter[T]{writer: w, encoder: encoder, err: err}
+}
+
+// Write a single value out as a Paragraph. As with the Encoder, subsequent
+// Paragraphs are separated with a blank line.
+func (w *TypedWriter[T]) Write(v T) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	data := reflect.ValueOf(&v).Elem()
+	for data.Kind() == reflect.Ptr {
+		if data.IsNil() {
+			return fmt.Errorf("Can't write a nil %s", data.Type())
+		}
+		data = data.Elem()
+	}
+
+	w.buf.Reset()
+	if w.alreadyWritten {
+		w.buf.WriteString("\n")
+	}
+	if err := w.encoder.encode(&w.buf, data); err != nil {
+		return err
+	}
+	w.alreadyWritten = true
+	_, err := w.writer.Write(w.buf.Bytes())
+	return err
+}
+
+// }}}
+
+// typedEncoder {{{
+
+// The cached layout of a struct type, as used by the TypedWriter.
+type typedEncoder struct {
+	/* The index of the anonymous Paragraph member, or -1 if there is
+	 * no such member. */
+	paragraph int
+	fields    []typedField
+	keys      map[string]int
+}
+
+type typedField struct {
+	index     int
+	key       string
+	required  bool
+	multiline bool
+	omitempty bool
+	fieldType reflect.StructField
+}
+
+var typedEncoders sync.Map
+
+func typedEncoderFor(t reflect.Type) (*typedEncoder, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Can only write a Struct, not a %s", t)
+	}
+
+	if encoder, ok := typedEncoders.Load(t); ok {
+		return encoder.(*typedEncoder), nil
+	}
+
+	paragraphType := reflect.TypeOf(Paragraph{})
+	encoder := typedEncoder{paragraph: -1, keys: map[string]int{}}
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+
+		if fieldType.Anonymous {
+			if fieldType.Type == paragraphType {
+				encoder.paragraph = i
+			}
+			continue
+		}
+
+		paragraphKey, omitempty := controlTag(fieldType)
+		if paragraphKey == "-" {
+			continue
+		}
+
+		encoder.keys[paragraphKey] = len(encoder.fields)
+		encoder.fields = append(encoder.fields, typedField{
+			index:     i,
+			key:       paragraphKey,
+			required:  fieldType.Tag.Get("required") == "true",
+			multiline: fieldType.Tag.Get("multiline") == "true",
+			omitempty: omitempty,
+			fieldType: fieldType,
+		})
+	}
+
+	cached, _ := typedEncoders.LoadOrStore(t, &encoder)
+	return cached.(*typedEncoder), nil
+}
+
+// Write the struct out to the buffer, exactly as ConvertToParagraph and
+// Paragraph.WriteTo would have; that is, the fields of an anonymous
+// Paragraph member come first (with their values replaced by those of the
+// struct members), followed by the rest of the struct members in order.
+func (e *typedEncoder) encode(buf *bytes.Buffer, data reflect.Value) error {
+	values := make([]string, len(e.fields))
+	present := make([]bool, len(e.fields))
+	for i, field := range e.fields {
+		if field.omitempty && data.Field(field.index).IsZero() {
+			continue
+		}
+		value, err := marshalStructValue(data.Field(field.index), field.fieldType)
+		if err != nil {
+			return err
+		}
+		if value == "" && !field.required {
+			continue
+		}
+		if field.multiline {
+			value = "\n" + value
+		}
+		values[i] = value
+		present[i] = true
+	}
+
+	written := make([]bool, len(e.fields))
+	if e.paragraph >= 0 {
+		para := data.Field(e.paragraph).Interface().(Paragraph)
+		for _, key := range para.Order {
+			value := para.Values[key]
+			if i, ok := e.keys[key]; ok && present[i] {
+				value = values[i]
+				written[i] = true
+			}
+			buf.WriteString(formatField(key, value))
+		}
+	}
+
+	for i, field := range e.fields {
+		if present[i] && !written[i] {
+			buf.WriteString(formatField(field.key, values[i]))
+		}
+	}
+	return nil
+}
+
+// }}}
+
+// vim: foldmethod=marker
@@ -0,0 +1,80 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package control_test
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+/*
+ *
+ */
+
+func TestTypedWriter(t *testing.T) {
+	pkgs, err := control.ParseBinaryIndex(bufio.NewReader(strings.NewReader(`Package: hello
+Version: 2.10-3
+Architecture: amd64
+X-Extra: kept
+Description: example package based on GNU hello
+ The GNU hello program produces a familiar, friendly greeting.
+ .
+ Seriously, it's a great program.
+Tag: role::program, use::printing
+
+Package: fbautostart
+Version: 2.718281828-1
+Architecture: amd64
+Installed-Size: 60
+`)))
+	isok(t, err)
+	assert(t, len(pkgs) == 2)
+	pkgs[1].Maintainer = "Paul Tagliamonte <paultag@debian.org>"
+
+	expected := bytes.Buffer{}
+	isok(t, control.Marshal(&expected, pkgs))
+
+	got := bytes.Buffer{}
+	writer := control.NewTypedWriter[control.BinaryIndex](&got)
+	for _, pkg := range pkgs {
+		isok(t, writer.Write(pkg))
+	}
+	assert(t, got.String() == expected.String())
+	assert(t, strings.Contains(got.String(), "X-Extra: kept\n"))
+
+	got.Reset()
+	ptrWriter := control.NewTypedWriter[*control.BinaryIndex](&got)
+	isok(t, ptrWriter.Write(&pkgs[0]))
+	isok(t, ptrWriter.Write(&pkgs[1]))
+	assert(t, got.String() == expected.String())
+	notok(t, ptrWriter.Write(nil))
+}
+
+func TestTypedWriterNotStruct(t *testing.T) {
+	writer := control.NewTypedWriter[string](&bytes.Buffer{})
+	notok(t, writer.Write("Package: hello"))
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,151 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/hashio"
+)
+
+// Add the .deb at the given path to the .changes, as when building up an
+// upload. The .deb is listed in the Files, Checksums-Sha1 and
+// Checksums-Sha256 fields by its base name, so it's expected to sit next to
+// the .changes, and its Package and Architecture are added to the Binary
+// and Architecture fields if they're not already there.
+//
+// Both the members of the Changes and its Paragraph are updated, so that
+// the Changes may be written out with either the Encoder or
+// Paragraph.WriteTo.
+func AddToChanges(changes *control.Changes, debPath string) error {
+	debFile, closer, err := LoadFile(debPath)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	stream, err := debFile.Stream()
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	writer, hashers, err := hashio.NewHasherWriters([]string{"md5", "sha1", "sha256"}, io.Discard)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(writer, stream); err != nil {
+		return err
+	}
+
+	filename := filepath.Base(debPath)
+	md5 := control.FileListChangesFileHash{
+		FileHash:  control.FileHashFromHasher(filename, *hashers[0]),
+		Component: orDash(debFile.Control.Section),
+		Priority:  orDash(debFile.Control.Priority),
+	}
+	changes.Files = append(changes.Files, md5)
+	changes.ChecksumsSha1 = append(changes.ChecksumsSha1, control.SHA1FileHash{
+		FileHash: control.FileHashFromHasher(filename, *hashers[1]),
+	})
+	changes.ChecksumsSha256 = append(changes.ChecksumsSha256, control.SHA256FileHash{
+		FileHash: control.FileHashFromHasher(filename, *hashers[2]),
+	})
+
+	hasBinary := false
+	for _, binary := range changes.Binaries {
+		hasBinary = hasBinary || binary == debFile.Control.Package
+	}
+	if !hasBinary {
+		changes.Binaries = append(changes.Binaries, debFile.Control.Package)
+	}
+
+	hasArch := false
+	for _, arch := range changes.Architectures {
+		hasArch = hasArch || arch == debFile.Control.Architecture
+	}
+	if !hasArch {
+		changes.Architectures = append(changes.Architectures, debFile.Control.Architecture)
+	}
+
+	if changes.Values == nil {
+		return nil
+	}
+	return updateChangesParagraph(changes)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// Update the Binary, Architecture, and file list fields of the Paragraph
+// of the Changes to match the members of the Changes.
+func updateChangesParagraph(changes *control.Changes) error {
+	architectures := []string{}
+	for _, arch := range changes.Architectures {
+		architectures = append(architectures, arch.String())
+	}
+	changes.Set("Binary", strings.Join(changes.Binaries, " "))
+	changes.Set("Architecture", strings.Join(architectures, " "))
+
+	files := []control.Marshallable{}
+	for _, file := range changes.ChecksumsSha1 {
+		files = append(files, file)
+	}
+	if err := setFileList(changes, "Checksums-Sha1", files); err != nil {
+		return err
+	}
+
+	files = []control.Marshallable{}
+	for _, file := range changes.ChecksumsSha256 {
+		files = append(files, file)
+	}
+	if err := setFileList(changes, "Checksums-Sha256", files); err != nil {
+		return err
+	}
+
+	files = []control.Marshallable{}
+	for _, file := range changes.Files {
+		files = append(files, file)
+	}
+	return setFileList(changes, "Files", files)
+}
+
+func setFileList(changes *control.Changes, field string, files []control.Marshallable) error {
+	lines := []string{}
+	for _, file := range files {
+		line, err := file.MarshalControl()
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line)
+	}
+	changes.Set(field, "\n"+strings.Join(lines, "\n"))
+	return nil
+}
+
+// vim: foldmethod=marker
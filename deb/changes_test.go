@@ -0,0 +1,75 @@
+package deb_test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/deb"
+)
+
+/*
+ *
+ */
+
+func TestAddToChanges(t *testing.T) {
+	_, debBytes := buildDeb(t, testControl, nil, nil)
+	debPath := filepath.Join(t.TempDir(), "hello_2.10-2_amd64.deb")
+	isok(t, os.WriteFile(debPath, debBytes, 0644))
+
+	changes, err := control.ParseChanges(bufio.NewReader(strings.NewReader(`Format: 1.8
+Source: hello
+Binary: hello-doc
+Architecture: source all
+Version: 2.10-2
+`)), "")
+	isok(t, err)
+
+	isok(t, deb.AddToChanges(changes, debPath))
+
+	assert(t, strings.Join(changes.Binaries, " ") == "hello-doc hello")
+	assert(t, len(changes.Architectures) == 3)
+	assert(t, changes.Architectures[2].CPU == "amd64")
+
+	assert(t, len(changes.Files) == 1)
+	file := changes.Files[0]
+	assert(t, file.Filename == "hello_2.10-2_amd64.deb")
+	assert(t, file.Size == int64(len(debBytes)))
+	assert(t, file.Hash == fmt.Sprintf("%x", md5.Sum(debBytes)))
+	assert(t, file.Component == "devel")
+	assert(t, file.Priority == "optional")
+
+	assert(t, len(changes.ChecksumsSha1) == 1)
+	assert(t, len(changes.ChecksumsSha256) == 1)
+	assert(t, changes.ChecksumsSha256[0].Hash == fmt.Sprintf("%x", sha256.Sum256(debBytes)))
+
+	assert(t, changes.Values["Binary"] == "hello-doc hello")
+	assert(t, changes.Values["Architecture"] == "source all amd64")
+	assert(t, changes.Values["Files"] == fmt.Sprintf("\n%x %d devel optional hello_2.10-2_amd64.deb",
+		md5.Sum(debBytes), len(debBytes)))
+
+	/* Adding the same package again lists the file twice, but doesn't
+	 * repeat the Binary or Architecture. */
+	isok(t, deb.AddToChanges(changes, debPath))
+	assert(t, len(changes.Files) == 2)
+	assert(t, len(changes.Binaries) == 2)
+	assert(t, len(changes.Architectures) == 3)
+
+	buf := bytes.Buffer{}
+	isok(t, control.Marshal(&buf, changes))
+	reparsed, err := control.ParseChanges(bufio.NewReader(&buf), "")
+	isok(t, err)
+	assert(t, len(reparsed.Files) == 2)
+	assert(t, reparsed.Files[1].Hash == file.Hash)
+
+	notok(t, deb.AddToChanges(changes, filepath.Join(t.TempDir(), "missing.deb")))
+}
+
+// vim: foldmethod=marker
@@ -23,6 +23,7 @@ package deb // import "github.com/akozlenkov/go-debian/deb"
 import (
 	"archive/tar"
 	"bufio"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"os"
@@ -84,6 +85,48 @@ type Deb struct {
 	ControlExt string
 	DataExt    string
 	ArContent  map[string]*ArEntry
+
+	in   io.ReaderAt
+	size int64
+
+	// The largest file ReadFile will read; see SetReadFileLimit.
+	readFileLimit int64
+}
+
+// Return the size of the .deb archive, in bytes.
+func (deb *Deb) Size() int64 {
+	return deb.size
+}
+
+// Return a reader of the raw bytes of the .deb archive (as it was given to
+// Load), from the very start of the archive. This is handy to forward a .deb
+// elsewhere without having to write it out to disk. Closing the returned
+// reader does not close the Deb.
+func (deb *Deb) Stream() (io.ReadCloser, error) {
+	if deb.in == nil {
+		return nil, fmt.Errorf("Deb was not loaded from a reader")
+	}
+	return io.NopCloser(io.NewSectionReader(deb.in, 0, deb.size)), nil
+}
+
+// Return the SHA-256 hash of the complete .deb archive. Unlike the hex
+// strings used in the control files, the hash is returned as an array, so
+// it can be used directly as a map key, which is handy for content
+// addressed storage.
+func (deb *Deb) ContentHash() ([32]byte, error) {
+	var ret [32]byte
+	stream, err := deb.Stream()
+	if err != nil {
+		return ret, err
+	}
+	defer stream.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, stream); err != nil {
+		return ret, err
+	}
+	copy(ret[:], hash.Sum(nil))
+	return ret, nil
 }
 
 func (deb *Deb) Close() error {
@@ -110,6 +153,15 @@ func Load(in io.ReaderAt, pathname string) (*Deb, error) {
 		return nil, err
 	}
 	deb.Path = pathname
+	deb.in = in
+	deb.size = ar.offset
+	if deb.size > 0 {
+		/* The final member may be missing its padding byte, in which case
+		 * the archive ends a byte before the offset of the next member. */
+		if n, _ := in.ReadAt(make([]byte, 1), deb.size-1); n != 1 {
+			deb.size--
+		}
+	}
 	return deb, nil
 }
 
@@ -0,0 +1,349 @@
+package deb_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/deb"
+)
+
+/*
+ *
+ */
+
+type testFile struct {
+	Name string
+	Body string
+	Mode int64
+	Dir  bool
+	// If set, the file is a hard link to the named file.
+	Link string
+}
+
+const testControl = `Package: hello
+Source: hello
+Version: 2.10-2
+Architecture: amd64
+Maintainer: Santiago Vila <sanvila@debian.org>
+Installed-Size: 280
+Depends: libc6 (>= 2.14)
+Section: devel
+Priority: optional
+Homepage: http://www.gnu.org/software/hello/
+Description: example package based on GNU hello
+ The GNU hello program produces a familiar, friendly greeting.
+`
+
+// Build a gzip compressed tar archive out of the given files.
+func buildTarGz(t *testing.T, files []testFile) []byte {
+	t.Helper()
+	buf := bytes.Buffer{}
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, file := range files {
+		hdr := tar.Header{
+			Name:     file.Name,
+			Mode:     file.Mode,
+			Size:     int64(len(file.Body)),
+			Typeflag: tar.TypeReg,
+		}
+		if file.Dir {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Size = 0
+		}
+		if file.Link != "" {
+			hdr.Typeflag = tar.TypeLink
+			hdr.Linkname = file.Link
+			hdr.Size = 0
+		}
+		if hdr.Mode == 0 {
+			hdr.Mode = 0644
+		}
+		isok(t, tw.WriteHeader(&hdr))
+		_, err := tw.Write([]byte(file.Body))
+		isok(t, err)
+	}
+	isok(t, tw.Close())
+	isok(t, gz.Close())
+	return buf.Bytes()
+}
+
+// Build an ar(1) archive out of the given (ordered) name / body pairs.
+func buildAr(members ...[2]string) []byte {
+	buf := bytes.Buffer{}
+	buf.WriteString("!<arch>\n")
+	for _, member := range members {
+		fmt.Fprintf(&buf, "%-16s%-12d%-6d%-6d%-8s%-10d`\n",
+			member[0], 0, 0, 0, "100644", len(member[1]))
+		buf.WriteString(member[1])
+		if len(member[1])%2 == 1 {
+			buf.WriteString("\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// Build a .deb out of the given control (in addition to the control file
+// itself) and data files, and load it.
+func buildDeb(t *testing.T, control string, controlFiles, dataFiles []testFile) (*deb.Deb, []byte) {
+	t.Helper()
+	controlFiles = append([]testFile{{Name: "./control", Body: control}}, controlFiles...)
+	debFile := buildAr(
+		[2]string{"debian-binary", "2.0\n"},
+		[2]string{"control.tar.gz", string(buildTarGz(t, controlFiles))},
+		[2]string{"data.tar.gz", string(buildTarGz(t, dataFiles))},
+	)
+	debObj, err := deb.Load(bytes.NewReader(debFile), "hello_2.10-2_amd64.deb")
+	isok(t, err)
+	return debObj, debFile
+}
+
+func TestLoad(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/bin/hello", Body: "#!/bin/sh\necho hello\n", Mode: 0755},
+	})
+	defer debFile.Close()
+
+	assert(t, debFile.Control.Package == "hello")
+	assert(t, debFile.Control.Version.Version == "2.10")
+	assert(t, debFile.ControlExt == "tar.gz")
+	assert(t, debFile.DataExt == "tar.gz")
+
+	hdr, err := debFile.Data.Next()
+	isok(t, err)
+	assert(t, hdr.Name == "./usr/bin/hello")
+}
+
+func TestRawControlTar(t *testing.T) {
+	debFile, raw := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	controlTar, format, err := debFile.RawControlTar()
+	isok(t, err)
+	assert(t, format == "gz")
+	assert(t, len(controlTar) == int(debFile.ArContent["control.tar.gz"].Size))
+	assert(t, bytes.Contains(raw, controlTar))
+}
+
+func TestStream(t *testing.T) {
+	debFile, raw := buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/bin/hello", Body: "#!/bin/sh\necho hello\n", Mode: 0755},
+	})
+	defer debFile.Close()
+
+	assert(t, debFile.Size() == int64(len(raw)))
+
+	stream, err := debFile.Stream()
+	isok(t, err)
+	data, err := io.ReadAll(stream)
+	isok(t, err)
+	isok(t, stream.Close())
+	assert(t, bytes.Equal(data, raw))
+
+	/* An odd sized final member, without its trailing padding byte */
+	raw = buildAr(
+		[2]string{"debian-binary", "2.0\n"},
+		[2]string{"control.tar.gz", string(buildTarGz(t, []testFile{{Name: "./control", Body: testControl}}))},
+		[2]string{"data.tar.gz", string(buildTarGz(t, nil))},
+		[2]string{"_extra", "odd"},
+	)
+	raw = raw[:len(raw)-1]
+	debFile, err = deb.Load(bytes.NewReader(raw), "hello_2.10-2_amd64.deb")
+	isok(t, err)
+	assert(t, debFile.Size() == int64(len(raw)))
+	isok(t, debFile.Close())
+
+	_, err = (&deb.Deb{}).Stream()
+	notok(t, err)
+}
+
+func TestDataTarCompression(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	format, err := debFile.DataTarCompression()
+	isok(t, err)
+	assert(t, format == deb.CompressionGzip)
+
+	for name, expected := range map[string]deb.CompressionFormat{
+		"data.tar":     deb.CompressionNone,
+		"data.tar.xz":  deb.CompressionXZ,
+		"data.tar.zst": deb.CompressionZstd,
+	} {
+		debFile := deb.Deb{ArContent: map[string]*deb.ArEntry{name: {Name: name}}}
+		format, err := debFile.DataTarCompression()
+		isok(t, err)
+		assert(t, format == expected)
+	}
+
+	for _, name := range []string{"data.tar.lz4", "data.zip"} {
+		debFile := deb.Deb{ArContent: map[string]*deb.ArEntry{name: {Name: name}}}
+		_, err := debFile.DataTarCompression()
+		notok(t, err)
+	}
+}
+
+func TestDataFileStat(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/", Dir: true, Mode: 0755},
+		{Name: "./usr/bin/hello", Body: "#!/bin/sh\necho hello\n", Mode: 0755},
+		{Name: "./usr/share/doc/hello/copyright", Body: "GPL-3+\n"},
+	})
+	defer debFile.Close()
+
+	for _, name := range []string{"usr/bin/hello", "/usr/bin/hello", "./usr/bin/hello"} {
+		hdr, err := debFile.DataFileStat(name)
+		isok(t, err)
+		assert(t, hdr.Name == "./usr/bin/hello")
+		assert(t, hdr.Mode == 0755)
+		assert(t, hdr.Size == 21)
+	}
+
+	hdr, err := debFile.DataFileStat("/usr")
+	isok(t, err)
+	assert(t, hdr.Typeflag == tar.TypeDir)
+
+	_, err = debFile.DataFileStat("/usr/bin/goodbye")
+	assert(t, errors.Is(err, deb.ErrFileNotFound))
+}
+
+func TestMemberNames(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	name, err := debFile.ControlTarName()
+	isok(t, err)
+	assert(t, name == "control.tar.gz")
+	name, err = debFile.DataTarName()
+	isok(t, err)
+	assert(t, name == "data.tar.gz")
+
+	debFile = &deb.Deb{ArContent: map[string]*deb.ArEntry{
+		"debian-binary":   {Name: "debian-binary"},
+		"control.tar.zst": {Name: "control.tar.zst"},
+	}}
+	name, err = debFile.ControlTarName()
+	isok(t, err)
+	assert(t, name == "control.tar.zst")
+	_, err = debFile.DataTarName()
+	notok(t, err)
+}
+
+func TestAllControlFiles(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, []testFile{
+		{Name: "./", Dir: true},
+		{Name: "./md5sums", Body: "d41d8cd98f00b204e9800998ecf8427e  usr/bin/hello\n"},
+		{Name: "./postinst", Body: "#!/bin/sh\n", Mode: 0755},
+		{Name: "./templates", Body: "Template: hello/greeting\nType: string\n"},
+	}, nil)
+	defer debFile.Close()
+
+	names, err := debFile.AllControlFiles()
+	isok(t, err)
+	assert(t, strings.Join(names, " ") == "control md5sums postinst templates")
+}
+
+func TestContentHash(t *testing.T) {
+	debFile, raw := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	hash, err := debFile.ContentHash()
+	isok(t, err)
+	assert(t, hash == sha256.Sum256(raw))
+
+	byHash := map[[32]byte]*deb.Deb{hash: debFile}
+	assert(t, byHash[sha256.Sum256(raw)] == debFile)
+
+	_, err = (&deb.Deb{}).ContentHash()
+	notok(t, err)
+}
+
+func TestInstalledSizeOnDisk(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/", Dir: true, Mode: 0755},
+		{Name: "./usr/bin/", Dir: true, Mode: 0755},
+		{Name: "./usr/bin/hello", Body: "#!/bin/sh\necho hello\n", Mode: 0755},
+		{Name: "./usr/share/doc/hello/copyright", Body: "GPL-3+\n"},
+	})
+	defer debFile.Close()
+
+	size, err := debFile.InstalledSizeOnDisk()
+	isok(t, err)
+	assert(t, size == 2*4096+21+7)
+
+	size, err = debFile.InstalledSizeOnDiskWithOptions(deb.SizeOptions{FileOverhead: 512})
+	isok(t, err)
+	assert(t, size == 2*512+21+7)
+}
+
+func TestReadFile(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/bin/", Dir: true, Mode: 0755},
+		{Name: "./usr/bin/hello", Body: "#!/bin/sh\necho hello\n", Mode: 0755},
+		{Name: "./usr/bin/hi", Link: "./usr/bin/hello"},
+		{Name: "./usr/share/doc/hello/copyright", Body: "GPL-3+\n"},
+	})
+	defer debFile.Close()
+
+	for _, name := range []string{"usr/bin/hello", "/usr/bin/hello", "./usr/bin/hi"} {
+		data, err := debFile.ReadFile(name)
+		isok(t, err)
+		assert(t, string(data) == "#!/bin/sh\necho hello\n")
+	}
+
+	data, err := debFile.ReadFile("/usr/share/doc/hello/copyright")
+	isok(t, err)
+	assert(t, string(data) == "GPL-3+\n")
+
+	_, err = debFile.ReadFile("/usr/bin")
+	notok(t, err)
+	_, err = debFile.ReadFile("/usr/bin/goodbye")
+	assert(t, errors.Is(err, deb.ErrFileNotFound))
+
+	debFile.SetReadFileLimit(10)
+	_, err = debFile.ReadFile("/usr/bin/hello")
+	assert(t, errors.Is(err, deb.ErrFileTooLarge))
+	data, err = debFile.ReadFile("/usr/share/doc/hello/copyright")
+	isok(t, err)
+	assert(t, string(data) == "GPL-3+\n")
+
+	debFile.SetReadFileLimit(0)
+	_, err = debFile.ReadFile("/usr/bin/hello")
+	isok(t, err)
+}
+
+func TestReadFileHardLinkLoop(t *testing.T) {
+	files := []testFile{
+		{Name: "./a", Link: "./b"},
+		{Name: "./b", Link: "./a"},
+		{Name: "./self", Link: "./self"},
+	}
+	for i := 0; i < 40; i++ {
+		files = append(files, testFile{
+			Name: fmt.Sprintf("./chain%d", i),
+			Link: fmt.Sprintf("./chain%d", i+1),
+		})
+	}
+	files = append(files, testFile{Name: "./chain40", Body: "end\n"})
+
+	debFile, _ := buildDeb(t, testControl, nil, files)
+	defer debFile.Close()
+
+	for _, name := range []string{"/a", "/b", "/self", "/chain0"} {
+		_, err := debFile.ReadFile(name)
+		notok(t, err)
+	}
+
+	data, err := debFile.ReadFile("/chain20")
+	isok(t, err)
+	assert(t, string(data) == "end\n")
+}
+
+// vim: foldmethod=marker
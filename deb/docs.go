@@ -0,0 +1,124 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/akozlenkov/go-debian/changelog"
+	"github.com/akozlenkov/go-debian/control"
+)
+
+// Find the first of the given files in the data.tar, as with dataFile, and
+// return its contents, decompressing it if it's a `.gz` file. If none of
+// the files are present this returns nil without an error.
+func (deb *Deb) docFile(names ...string) ([]byte, error) {
+	name, data, err := deb.dataFile(names...)
+	if err != nil || name == "" {
+		return nil, err
+	}
+	if !strings.HasSuffix(name, ".gz") {
+		return data, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// Return the contents of the package's README.Debian, which is installed to
+// /usr/share/doc/<package>/README.Debian, and often compressed as
+// README.Debian.gz, in which case it is decompressed. If the package
+// doesn't ship a README.Debian, this returns nil without an error.
+func (deb *Deb) ReadmeDebian() ([]byte, error) {
+	readme := fmt.Sprintf("/usr/share/doc/%s/README.Debian", deb.Control.Package)
+	return deb.docFile(readme, readme+".gz")
+}
+
+// Return the decompressed contents of the package's Debian changelog, which
+// is installed to /usr/share/doc/<package>/changelog.Debian.gz (or, for
+// native packages, /usr/share/doc/<package>/changelog.gz). If the package
+// doesn't ship a changelog, this returns nil without an error.
+func (deb *Deb) ChangelogGz() ([]byte, error) {
+	doc := fmt.Sprintf("/usr/share/doc/%s/", deb.Control.Package)
+	if deb.Control.Version.IsNative() {
+		return deb.docFile(doc+"changelog.Debian.gz", doc+"changelog.gz")
+	}
+	return deb.docFile(doc + "changelog.Debian.gz")
+}
+
+// Parse the package's Debian changelog, as returned by ChangelogGz, into
+// its entries. If the package doesn't ship a changelog, this returns nil
+// without an error.
+func (deb *Deb) Changelog() (changelog.ChangelogEntries, error) {
+	data, err := deb.ChangelogGz()
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return changelog.Parse(bytes.NewReader(data))
+}
+
+// Return the contents of the package's copyright file, which is installed
+// to /usr/share/doc/<package>/copyright. If the package doesn't ship a
+// copyright file, this returns nil without an error.
+func (deb *Deb) CopyrightText() ([]byte, error) {
+	return deb.docFile(fmt.Sprintf("/usr/share/doc/%s/copyright", deb.Control.Package))
+}
+
+// For packages with a DEP-5 (machine-readable) copyright file, return the
+// short name of the License of the `Files: *` paragraph, such as
+// `GPL-3+`, which is usually an SPDX identifier, or close to one. If the
+// package doesn't ship a copyright file, or it isn't in the DEP-5 format,
+// this returns an empty string without an error.
+func (deb *Deb) SPDX() (string, error) {
+	data, err := deb.CopyrightText()
+	if err != nil || !bytes.HasPrefix(data, []byte("Format:")) {
+		return "", err
+	}
+
+	reader, err := control.NewParagraphReader(bytes.NewReader(data), nil)
+	if err != nil {
+		return "", err
+	}
+	for {
+		para, err := reader.Next()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(para.Values["Files"]) != "*" {
+			continue
+		}
+		license := strings.SplitN(para.Values["License"], "\n", 2)[0]
+		return strings.TrimSpace(license), nil
+	}
+}
+
+// vim: foldmethod=marker
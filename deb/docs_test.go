@@ -0,0 +1,163 @@
+package deb_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+)
+
+/*
+ *
+ */
+
+const testReadme = `hello for Debian
+----------------
+
+This is a very friendly package.
+`
+
+func TestReadmeDebian(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/share/doc/hello/README.Debian", Body: testReadme},
+	})
+	defer debFile.Close()
+	readme, err := debFile.ReadmeDebian()
+	isok(t, err)
+	assert(t, string(readme) == testReadme)
+
+	debFile, _ = buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/share/doc/hello/", Dir: true},
+		{Name: "./usr/share/doc/hello/README.Debian.gz", Body: gzipString(t, testReadme)},
+	})
+	defer debFile.Close()
+	readme, err = debFile.ReadmeDebian()
+	isok(t, err)
+	assert(t, string(readme) == testReadme)
+
+	debFile, _ = buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/share/doc/hello-doc/README.Debian", Body: testReadme},
+	})
+	defer debFile.Close()
+	readme, err = debFile.ReadmeDebian()
+	isok(t, err)
+	assert(t, readme == nil)
+}
+
+const testChangelog = `hello (2.10-2) unstable; urgency=low
+
+  * Rebuild for the test suite.
+
+ -- Santiago Vila <sanvila@debian.org>  Sat, 13 Aug 2016 22:12:48 +0200
+
+hello (2.10-1) unstable; urgency=low
+
+  * New upstream release.
+
+ -- Santiago Vila <sanvila@debian.org>  Sun, 05 Jun 2016 10:02:33 +0200
+`
+
+func gzipString(t *testing.T, in string) string {
+	t.Helper()
+	compressed := bytes.Buffer{}
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write([]byte(in))
+	isok(t, err)
+	isok(t, gz.Close())
+	return compressed.String()
+}
+
+func TestChangelog(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/share/doc/hello/changelog.gz", Body: gzipString(t, "upstream changes\n")},
+		{Name: "./usr/share/doc/hello/changelog.Debian.gz", Body: gzipString(t, testChangelog)},
+	})
+	defer debFile.Close()
+
+	data, err := debFile.ChangelogGz()
+	isok(t, err)
+	assert(t, string(data) == testChangelog)
+
+	entries, err := debFile.Changelog()
+	isok(t, err)
+	assert(t, len(entries) == 2)
+	assert(t, entries[0].Version.String() == "2.10-2")
+	assert(t, entries[1].Version.String() == "2.10-1")
+
+	debFile, _ = buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/share/doc/hello/changelog.gz", Body: gzipString(t, "upstream changes\n")},
+	})
+	defer debFile.Close()
+	data, err = debFile.ChangelogGz()
+	isok(t, err)
+	assert(t, data == nil)
+	entries, err = debFile.Changelog()
+	isok(t, err)
+	assert(t, entries == nil)
+
+	debFile.Control.Version.Revision = ""
+	data, err = debFile.ChangelogGz()
+	isok(t, err)
+	assert(t, string(data) == "upstream changes\n")
+
+	// A native package prefers changelog.Debian.gz, wherever it is in the
+	// data.tar.
+	debFile, _ = buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/share/doc/hello/changelog.gz", Body: gzipString(t, "upstream changes\n")},
+		{Name: "./usr/share/doc/hello/changelog.Debian.gz", Body: gzipString(t, testChangelog)},
+	})
+	defer debFile.Close()
+	debFile.Control.Version.Revision = ""
+	data, err = debFile.ChangelogGz()
+	isok(t, err)
+	assert(t, string(data) == testChangelog)
+}
+
+const testCopyright = `Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+Upstream-Name: hello
+Source: https://ftp.gnu.org/gnu/hello/
+
+Files: debian/*
+Copyright: 1992-2016 Santiago Vila <sanvila@debian.org>
+License: GPL-2+
+
+Files: *
+Copyright: 1992-2014 Free Software Foundation, Inc.
+License: GPL-3+
+ This program is free software; you can redistribute it and/or modify
+ it under the terms of the GNU General Public License as published by
+ the Free Software Foundation; either version 3, or (at your option)
+ any later version.
+`
+
+func TestCopyright(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/share/doc/hello/copyright", Body: testCopyright},
+	})
+	defer debFile.Close()
+
+	data, err := debFile.CopyrightText()
+	isok(t, err)
+	assert(t, string(data) == testCopyright)
+	license, err := debFile.SPDX()
+	isok(t, err)
+	assert(t, license == "GPL-3+")
+
+	debFile, _ = buildDeb(t, testControl, nil, []testFile{
+		{Name: "./usr/share/doc/hello/copyright", Body: "This is hello, written by the FSF.\n\nIt is under the GPL.\n"},
+	})
+	defer debFile.Close()
+	license, err = debFile.SPDX()
+	isok(t, err)
+	assert(t, license == "")
+
+	debFile, _ = buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+	data, err = debFile.CopyrightText()
+	isok(t, err)
+	assert(t, data == nil)
+	license, err = debFile.SPDX()
+	isok(t, err)
+	assert(t, license == "")
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,185 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/dependency"
+)
+
+// Parse the Maintainer field of the .deb's control file into the display
+// name and email address of the Maintainer. Both the `Name <email>` and
+// older `email (Name)` forms are understood, as are bare email addresses
+// (in which case the name is empty) and RFC 2047 encoded names.
+func (deb *Deb) Maintainer() (name, email string, err error) {
+	maintainer := strings.TrimSpace(deb.Control.Maintainer)
+	if maintainer == "" {
+		return "", "", fmt.Errorf("Package '%s' has no Maintainer", deb.Control.Package)
+	}
+	return control.ParseEmailAddress(maintainer)
+}
+
+// Return the Homepage of the package, or an empty string if the package
+// doesn't have a Homepage field.
+func (deb *Deb) Homepage() (string, error) {
+	return strings.TrimSpace(deb.Control.Homepage), nil
+}
+
+// Return the Homepage of the package, parsed as a URL. If the package
+// doesn't have a Homepage field, this returns nil without an error.
+func (deb *Deb) HomepageURL() (*url.URL, error) {
+	homepage, err := deb.Homepage()
+	if err != nil || homepage == "" {
+		return nil, err
+	}
+	return url.Parse(homepage)
+}
+
+// Return the Depends relationships of the package, which must be satisfied
+// for the package to be configured.
+func (deb *Deb) Depends() (dependency.Dependency, error) {
+	return deb.Control.Depends, nil
+}
+
+// Return the Recommends relationships of the package, which are installed
+// along with it by default, but aren't strictly required.
+func (deb *Deb) Recommends() (dependency.Dependency, error) {
+	return deb.Control.Recommends, nil
+}
+
+// Return the Suggests relationships of the package, which may enhance its
+// usefulness, but aren't installed by default.
+func (deb *Deb) Suggests() (dependency.Dependency, error) {
+	return deb.Control.Suggests, nil
+}
+
+// Suffixes of the names of packages built alongside (and named after) a
+// main package, checked in order, so that `-dbgsym` is tried before `-dbg`.
+var derivedPackageSuffixes = []string{
+	"-dbgsym",
+	"-dbg",
+	"-doc",
+	"-dev",
+	"-common",
+	"-data",
+}
+
+// Guess the name of the main package this package was derived from, by
+// stripping a well known suffix such as `-dbgsym`, `-doc` or `-dev` from
+// the package name, so `hello-dbgsym` becomes `hello`. Only one suffix is
+// stripped, and the name is returned as-is if it has none.
+//
+// This is only a heuristic, since (for instance) the main package that
+// `libfoo-dev` goes with is usually `libfoo1`, not `libfoo`.
+func (deb *Deb) OriginalPackageName() string {
+	name := deb.Control.Package
+	for _, suffix := range derivedPackageSuffixes {
+		if base := strings.TrimSuffix(name, suffix); base != name && base != "" {
+			return base
+		}
+	}
+	return name
+}
+
+// VcsInfo describes where the packaging of a package is kept under version
+// control, as given by the Vcs-* fields.
+type VcsInfo struct {
+	// The kind of version control system, such as `git`, `svn`, `bzr`
+	// or `hg`, always in lowercase.
+	Type string
+
+	URL string
+
+	// The branch, for git repositories given as `URL -b branch`, or
+	// Subversion URLs into the `branches/` directory.
+	Branch string
+
+	// The tag, for Subversion URLs into the `tags/` directory.
+	Tag string
+}
+
+// Parse the Vcs-* fields of the .deb's control file into a VcsInfo. The
+// Vcs-Browser field is not considered, since it points at a web view of the
+// repository rather than the repository itself. If the package has no Vcs-*
+// fields, this returns nil without an error.
+func (deb *Deb) VcsInfo() (*VcsInfo, error) {
+	var ret *VcsInfo
+	for _, field := range deb.Control.GlobGet("Vcs-*") {
+		vcsType := strings.ToLower(strings.TrimPrefix(field.Name, "Vcs-"))
+		if vcsType == "browser" {
+			continue
+		}
+		if ret != nil {
+			return nil, fmt.Errorf("Package '%s' has more than one Vcs field", deb.Control.Package)
+		}
+
+		info, err := parseVcsInfo(vcsType, field.Value)
+		if err != nil {
+			return nil, err
+		}
+		ret = info
+	}
+	return ret, nil
+}
+
+func parseVcsInfo(vcsType, value string) (*VcsInfo, error) {
+	ret := VcsInfo{Type: vcsType}
+
+	words := strings.Fields(value)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("Empty Vcs-%s field", vcsType)
+	}
+	ret.URL = words[0]
+
+	if vcsType == "git" {
+		/* Vcs-Git: url [-b branch] [[path]] */
+		for i := 1; i < len(words); i++ {
+			if words[i] != "-b" {
+				continue
+			}
+			if i+1 >= len(words) || strings.HasPrefix(words[i+1], "[") {
+				return nil, fmt.Errorf("Vcs-Git field '%s' has no branch after '-b'", value)
+			}
+			ret.Branch = words[i+1]
+			i++
+		}
+	}
+
+	if vcsType == "svn" {
+		parts := strings.Split(strings.TrimSuffix(ret.URL, "/"), "/")
+		for i := 0; i+1 < len(parts); i++ {
+			switch parts[i] {
+			case "branches":
+				ret.Branch = parts[i+1]
+			case "tags":
+				ret.Tag = parts[i+1]
+			}
+		}
+	}
+
+	return &ret, nil
+}
+
+// vim: foldmethod=marker
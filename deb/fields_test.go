@@ -0,0 +1,153 @@
+package deb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/deb"
+)
+
+/*
+ *
+ */
+
+func TestMaintainer(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	name, email, err := debFile.Maintainer()
+	isok(t, err)
+	assert(t, name == "Santiago Vila")
+	assert(t, email == "sanvila@debian.org")
+
+	for maintainer, want := range map[string][2]string{
+		"sanvila@debian.org":                       {"", "sanvila@debian.org"},
+		"sanvila@debian.org (Santiago Vila)":       {"Santiago Vila", "sanvila@debian.org"},
+		`"Vila, Santiago" <sanvila@debian.org>`:    {"Vila, Santiago", "sanvila@debian.org"},
+		"Jörg Frings-Fürst <debian@jff.email>":     {"Jörg Frings-Fürst", "debian@jff.email"},
+		"=?UTF-8?Q?J=C3=B6rg?= <debian@jff.email>": {"Jörg", "debian@jff.email"},
+	} {
+		debFile := deb.Deb{Control: deb.Control{Maintainer: maintainer}}
+		name, email, err := debFile.Maintainer()
+		isok(t, err)
+		assert(t, name == want[0])
+		assert(t, email == want[1])
+	}
+
+	for _, maintainer := range []string{"", "Santiago Vila"} {
+		debFile := deb.Deb{Control: deb.Control{Maintainer: maintainer}}
+		_, _, err := debFile.Maintainer()
+		notok(t, err)
+	}
+}
+
+func TestHomepage(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	homepage, err := debFile.Homepage()
+	isok(t, err)
+	assert(t, homepage == "http://www.gnu.org/software/hello/")
+
+	homepageURL, err := debFile.HomepageURL()
+	isok(t, err)
+	assert(t, homepageURL.Host == "www.gnu.org")
+	assert(t, homepageURL.Path == "/software/hello/")
+
+	debFile.Control.Homepage = ""
+	homepage, err = debFile.Homepage()
+	isok(t, err)
+	assert(t, homepage == "")
+	homepageURL, err = debFile.HomepageURL()
+	isok(t, err)
+	assert(t, homepageURL == nil)
+
+	debFile.Control.Homepage = "http://[::1"
+	_, err = debFile.HomepageURL()
+	notok(t, err)
+}
+
+func TestRelationships(t *testing.T) {
+	debFile, _ := buildDeb(t, strings.Replace(testControl,
+		"Depends: libc6 (>= 2.14)\n",
+		"Depends: libc6 (>= 2.14)\nRecommends: hello-data | hello-data-minimal\nSuggests: hello-doc\n", 1),
+		nil, nil)
+	defer debFile.Close()
+
+	depends, err := debFile.Depends()
+	isok(t, err)
+	assert(t, len(depends.Relations) == 1)
+	assert(t, depends.Relations[0].Possibilities[0].Name == "libc6")
+
+	recommends, err := debFile.Recommends()
+	isok(t, err)
+	assert(t, len(recommends.Relations) == 1)
+	assert(t, len(recommends.Relations[0].Possibilities) == 2)
+	assert(t, recommends.Relations[0].Possibilities[1].Name == "hello-data-minimal")
+
+	suggests, err := debFile.Suggests()
+	isok(t, err)
+	assert(t, suggests.String() == "hello-doc")
+
+	debFile, _ = buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+	suggests, err = debFile.Suggests()
+	isok(t, err)
+	assert(t, len(suggests.Relations) == 0)
+}
+
+func TestOriginalPackageName(t *testing.T) {
+	for name, want := range map[string]string{
+		"hello":            "hello",
+		"hello-dbgsym":     "hello",
+		"hello-dbg":        "hello",
+		"hello-doc":        "hello",
+		"libhello-dev":     "libhello",
+		"hello-common":     "hello",
+		"hello-data":       "hello",
+		"hello-doc-dbgsym": "hello-doc",
+		"-dev":             "-dev",
+		"debhelper":        "debhelper",
+	} {
+		debFile := deb.Deb{Control: deb.Control{}}
+		debFile.Control.Package = name
+		assert(t, debFile.OriginalPackageName() == want)
+	}
+}
+
+func TestVcsInfo(t *testing.T) {
+	debFile := deb.Deb{}
+	debFile.Control.Values = map[string]string{}
+
+	info, err := debFile.VcsInfo()
+	isok(t, err)
+	assert(t, info == nil)
+
+	debFile.Control.Set("Vcs-Browser", "https://salsa.debian.org/sanvila/hello")
+	debFile.Control.Set("Vcs-Git", "https://salsa.debian.org/sanvila/hello.git -b debian/latest [debian]")
+	info, err = debFile.VcsInfo()
+	isok(t, err)
+	assert(t, info.Type == "git")
+	assert(t, info.URL == "https://salsa.debian.org/sanvila/hello.git")
+	assert(t, info.Branch == "debian/latest")
+	assert(t, info.Tag == "")
+
+	debFile.Control.Set("Vcs-Git", "https://salsa.debian.org/sanvila/hello.git -b")
+	_, err = debFile.VcsInfo()
+	notok(t, err)
+
+	debFile.Control.Set("Vcs-Svn", "svn://svn.debian.org/hello/tags/2.10-3/")
+	_, err = debFile.VcsInfo()
+	notok(t, err)
+
+	debFile.Control = deb.Control{}
+	debFile.Control.Values = map[string]string{}
+	debFile.Control.Set("Vcs-Svn", "svn://svn.debian.org/hello/tags/2.10-3/")
+	info, err = debFile.VcsInfo()
+	isok(t, err)
+	assert(t, info.Type == "svn")
+	assert(t, info.Tag == "2.10-3")
+	assert(t, info.Branch == "")
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,84 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Split a .deb filename (such as `pool/main/h/hello/hello_2.10-3_amd64.deb`)
+// into the package name, version and architecture, following the usual
+// `name_version_arch.deb` convention. Any leading directories are ignored,
+// and `.udeb` and `.ddeb` files are understood too.
+//
+// Since the epoch is dropped from the version in filenames, the returned
+// version never has one.
+func ParseDebFilename(filename string) (name, version, arch string, err error) {
+	base := path.Base(filename)
+	trimmed := base
+	for _, ext := range []string{".deb", ".udeb", ".ddeb"} {
+		if strings.HasSuffix(base, ext) {
+			trimmed = strings.TrimSuffix(base, ext)
+			break
+		}
+	}
+	if trimmed == base {
+		return "", "", "", fmt.Errorf("Filename '%s' is not a .deb", filename)
+	}
+
+	/* Neither package names, versions nor architectures may contain an
+	 * underscore, so there must be exactly three parts. */
+	parts := strings.Split(trimmed, "_")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("Malformed .deb filename '%s'", filename)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// Return the canonical filename for a .deb of the given package name,
+// version and architecture, such as `hello_2.10-3_amd64.deb`. As dpkg does,
+// any epoch is dropped from the version.
+func DebFilename(name, version, arch string) string {
+	if i := strings.Index(version, ":"); i >= 0 {
+		version = version[i+1:]
+	}
+	return fmt.Sprintf("%s_%s_%s.deb", name, version, arch)
+}
+
+// Return the canonical filename of the .deb, as computed from the Package,
+// Version and Architecture fields of its control file (rather than the name
+// of the file it was loaded from), such as `hello_2.10-3_amd64.deb`.
+func (deb *Deb) CanonicalFilename() (string, error) {
+	fields := deb.Control
+	switch {
+	case fields.Package == "":
+		return "", fmt.Errorf("Package has no Package field")
+	case fields.Version.Version == "":
+		return "", fmt.Errorf("Package '%s' has no Version field", fields.Package)
+	case fields.Architecture.CPU == "":
+		return "", fmt.Errorf("Package '%s' has no Architecture field", fields.Package)
+	}
+	return DebFilename(fields.Package, fields.Version.String(), fields.Architecture.String()), nil
+}
+
+// vim: foldmethod=marker
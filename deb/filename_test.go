@@ -0,0 +1,67 @@
+package deb_test
+
+import (
+	"testing"
+
+	"github.com/akozlenkov/go-debian/deb"
+)
+
+/*
+ *
+ */
+
+func TestParseDebFilename(t *testing.T) {
+	for filename, expected := range map[string][3]string{
+		"hello_2.10-3_amd64.deb":                           {"hello", "2.10-3", "amd64"},
+		"pool/main/h/hello/hello_2.10-3+b1_arm64.deb":      {"hello", "2.10-3+b1", "arm64"},
+		"libc6-udeb_2.36-9+deb12u4_amd64.udeb":             {"libc6-udeb", "2.36-9+deb12u4", "amd64"},
+		"hello-dbgsym_2.10~rc1-1~bpo12+1_all.ddeb":         {"hello-dbgsym", "2.10~rc1-1~bpo12+1", "all"},
+		"/srv/incoming/g++-12_12.2.0-14_kfreebsd-i386.deb": {"g++-12", "12.2.0-14", "kfreebsd-i386"},
+	} {
+		name, version, arch, err := deb.ParseDebFilename(filename)
+		isok(t, err)
+		assert(t, [3]string{name, version, arch} == expected)
+	}
+
+	for _, filename := range []string{
+		"hello_2.10-3_amd64.dsc",
+		"hello_2.10-3.deb",
+		"hello_2.10_3_amd64.deb",
+		"_2.10-3_amd64.deb",
+	} {
+		_, _, _, err := deb.ParseDebFilename(filename)
+		notok(t, err)
+	}
+}
+
+func TestDebFilename(t *testing.T) {
+	assert(t, deb.DebFilename("hello", "2.10-3", "amd64") == "hello_2.10-3_amd64.deb")
+	assert(t, deb.DebFilename("vim", "2:9.0.1378-2", "arm64") == "vim_9.0.1378-2_arm64.deb")
+
+	name, version, arch, err := deb.ParseDebFilename(deb.DebFilename("hello", "2.10~rc1-1", "all"))
+	isok(t, err)
+	assert(t, name == "hello" && version == "2.10~rc1-1" && arch == "all")
+}
+
+func TestCanonicalFilename(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	filename, err := debFile.CanonicalFilename()
+	isok(t, err)
+	assert(t, filename == "hello_2.10-2_amd64.deb")
+
+	debFile.Control.Version.Epoch = 1
+	filename, err = debFile.CanonicalFilename()
+	isok(t, err)
+	assert(t, filename == "hello_2.10-2_amd64.deb")
+
+	debFile.Control.Architecture.CPU = ""
+	_, err = debFile.CanonicalFilename()
+	notok(t, err)
+
+	_, err = (&deb.Deb{}).CanonicalFilename()
+	notok(t, err)
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,182 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/akozlenkov/go-debian/control"
+
+	"pault.ag/go/topsort"
+)
+
+// A PackageGraph is a directed graph of a set of binary packages, with an
+// edge from each package to each of the packages in the set which it
+// Depends or Pre-Depends on.
+type PackageGraph struct {
+	packages []*control.BinaryIndex
+	index    map[string]int
+	depends  [][]int
+}
+
+// Build the PackageGraph of the given set of packages. Relations on
+// packages outside of the set are ignored, as are relations which are only
+// satisfied by a Provides. For a relation with alternatives (`foo | bar`),
+// only the first alternative in the set is used. Each package may only
+// appear in the set once.
+func BuildDependencyGraph(pkgs []*control.BinaryIndex) (*PackageGraph, error) {
+	graph := PackageGraph{
+		packages: pkgs,
+		index:    map[string]int{},
+		depends:  make([][]int, len(pkgs)),
+	}
+
+	for i, pkg := range pkgs {
+		if _, ok := graph.index[pkg.Package]; ok {
+			return nil, fmt.Errorf("Package '%s' appears more than once", pkg.Package)
+		}
+		graph.index[pkg.Package] = i
+	}
+
+	for i, pkg := range pkgs {
+		depends := pkg.GetPreDepends()
+		depends.Relations = append(depends.Relations, pkg.GetDepends().Relations...)
+		for _, relation := range depends.Relations {
+			for _, possi := range relation.Possibilities {
+				if possi.Substvar {
+					continue
+				}
+				if j, ok := graph.index[possi.Name]; ok {
+					if j != i {
+						graph.depends[i] = append(graph.depends[i], j)
+					}
+					break
+				}
+			}
+		}
+	}
+
+	return &graph, nil
+}
+
+// Return the packages in the order they should be installed in, that is,
+// with each package following everything it depends on. If the packages
+// can't be ordered, due to a dependency cycle, this returns an error; use
+// FindCycles to see which packages are at fault.
+func (graph *PackageGraph) TopologicalOrder() ([]*control.BinaryIndex, error) {
+	network := topsort.NewNetwork()
+	for _, pkg := range graph.packages {
+		network.AddNode(pkg.Package, pkg)
+	}
+	for i, depends := range graph.depends {
+		for _, j := range depends {
+			if err := network.AddEdge(graph.packages[j].Package, graph.packages[i].Package); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	nodes, err := network.Sort()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*control.BinaryIndex{}
+	for _, node := range nodes {
+		ret = append(ret, node.Value.(*control.BinaryIndex))
+	}
+	return ret, nil
+}
+
+// Return each set of packages which depend on each other in a cycle. Each
+// cycle is returned in the order the packages were given to
+// BuildDependencyGraph, and the cycles are ordered by their first package.
+// If there are no cycles, the returned slice is empty.
+func (graph *PackageGraph) FindCycles() [][]*control.BinaryIndex {
+	/* This is Tarjan's strongly connected components algorithm; every
+	 * component with more than one package is a cycle. */
+	var (
+		next    = 0
+		indexOf = make([]int, len(graph.packages))
+		lowlink = make([]int, len(graph.packages))
+		onStack = make([]bool, len(graph.packages))
+		stack   = []int{}
+		cycles  = [][]int{}
+		visit   func(int)
+	)
+	for i := range indexOf {
+		indexOf[i] = -1
+	}
+
+	visit = func(v int) {
+		indexOf[v], lowlink[v] = next, next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range graph.depends[v] {
+			if indexOf[w] == -1 {
+				visit(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], indexOf[w])
+			}
+		}
+
+		if lowlink[v] != indexOf[v] {
+			return
+		}
+		component := []int{}
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		if len(component) > 1 {
+			sort.Ints(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for v := range graph.packages {
+		if indexOf[v] == -1 {
+			visit(v)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	ret := [][]*control.BinaryIndex{}
+	for _, component := range cycles {
+		cycle := []*control.BinaryIndex{}
+		for _, i := range component {
+			cycle = append(cycle, graph.packages[i])
+		}
+		ret = append(ret, cycle)
+	}
+	return ret
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,82 @@
+package deb_test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/deb"
+)
+
+/*
+ *
+ */
+
+func parseGraphPackages(t *testing.T, index string) []*control.BinaryIndex {
+	t.Helper()
+	pkgs, err := control.ParseBinaryIndex(bufio.NewReader(strings.NewReader(index)))
+	isok(t, err)
+	ret := []*control.BinaryIndex{}
+	for i := range pkgs {
+		ret = append(ret, &pkgs[i])
+	}
+	return ret
+}
+
+func packageNames(pkgs []*control.BinaryIndex) string {
+	names := []string{}
+	for _, pkg := range pkgs {
+		names = append(names, pkg.Package)
+	}
+	return strings.Join(names, " ")
+}
+
+func TestDependencyGraphOrder(t *testing.T) {
+	pkgs := parseGraphPackages(t, `Package: hello
+Depends: libc6 (>= 2.34), hello-data | hello-data-minimal
+
+Package: hello-data
+Depends: ${misc:Depends}
+
+Package: libc6
+Pre-Depends: libgcc-s1
+Depends: libc-bin
+
+Package: libgcc-s1
+Depends: libc6, gcc-12-base
+
+Package: gcc-12-base
+`)
+	graph, err := deb.BuildDependencyGraph(pkgs)
+	isok(t, err)
+
+	_, err = graph.TopologicalOrder()
+	notok(t, err)
+
+	cycles := graph.FindCycles()
+	assert(t, len(cycles) == 1)
+	assert(t, packageNames(cycles[0]) == "libc6 libgcc-s1")
+
+	pkgs[3].Values["Depends"] = "gcc-12-base"
+	graph, err = deb.BuildDependencyGraph(pkgs)
+	isok(t, err)
+	assert(t, len(graph.FindCycles()) == 0)
+
+	order, err := graph.TopologicalOrder()
+	isok(t, err)
+	assert(t, packageNames(order) == "hello-data gcc-12-base libgcc-s1 libc6 hello")
+}
+
+func TestDependencyGraphDuplicates(t *testing.T) {
+	pkgs := parseGraphPackages(t, `Package: hello
+Architecture: amd64
+
+Package: hello
+Architecture: i386
+`)
+	_, err := deb.BuildDependencyGraph(pkgs)
+	notok(t, err)
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,57 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+// Parse an `md5sums` control file, which has a line for each file installed
+// by the package, made up of the hex MD5 sum of the file, some whitespace,
+// and the path of the file (relative to the root directory). The returned
+// map is keyed by path, with the hex MD5 sum as the value.
+func ParseMD5Sums(r io.Reader) (map[string]string, error) {
+	return control.ParseInfoMD5Sums(r)
+}
+
+// Format a map of path to hex MD5 sum (as returned by ParseMD5Sums) as an
+// `md5sums` control file. Lines are sorted by path, so that the output is
+// reproducible.
+func FormatMD5Sums(m map[string]string) []byte {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	buf := bytes.Buffer{}
+	for _, path := range paths {
+		fmt.Fprintf(&buf, "%s  %s\n", m[path], path)
+	}
+	return buf.Bytes()
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,40 @@
+package deb_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/deb"
+)
+
+/*
+ *
+ */
+
+const testMD5Sums = `f1b5ba2d4dd6e7d322df0d0ba0a9237c  usr/bin/hello
+8a2a7a0ee58be5f8cf8a4cf43ae4c1ac  usr/share/doc/hello/NEWS.gz
+3b3c2a0e3e02ae8bdb2cd05b4a5e6e20  usr/share/doc/hello/with space
+`
+
+func TestParseMD5Sums(t *testing.T) {
+	sums, err := deb.ParseMD5Sums(strings.NewReader(testMD5Sums))
+	isok(t, err)
+	assert(t, len(sums) == 3)
+	assert(t, sums["usr/bin/hello"] == "f1b5ba2d4dd6e7d322df0d0ba0a9237c")
+	assert(t, sums["usr/share/doc/hello/with space"] == "3b3c2a0e3e02ae8bdb2cd05b4a5e6e20")
+
+	_, err = deb.ParseMD5Sums(strings.NewReader("usr/bin/hello\n"))
+	notok(t, err)
+
+	_, err = deb.ParseMD5Sums(strings.NewReader("nothex  usr/bin/hello\n"))
+	notok(t, err)
+}
+
+func TestFormatMD5Sums(t *testing.T) {
+	sums, err := deb.ParseMD5Sums(strings.NewReader(testMD5Sums))
+	isok(t, err)
+	assert(t, bytes.Equal(deb.FormatMD5Sums(sums), []byte(testMD5Sums)))
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,423 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Raw .deb members {{{
+
+// Find the ar(1) member whose name starts with the given prefix, such as
+// `control.` or `data.`.
+func (deb *Deb) findMember(prefix string) (*ArEntry, error) {
+	for name, member := range deb.ArContent {
+		if strings.HasPrefix(name, prefix) {
+			return member, nil
+		}
+	}
+	return nil, fmt.Errorf("Missing .deb member '%s*'", prefix)
+}
+
+// Return the compression format of a member extension, such as `gz` for
+// `tar.gz`, or an empty string for an uncompressed `tar`.
+func compressionOf(ext string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(ext, "tar"), ".")
+}
+
+// Open the tar archive contained in the ar(1) member whose name starts with
+// the given prefix. This reads the member from the start, and is safe to
+// call more than once.
+func (deb *Deb) openTar(prefix string) (*tar.Reader, io.Closer, error) {
+	member, err := deb.findMember(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	rewound := *member
+	rewound.Data = io.NewSectionReader(member.Data, 0, member.Size)
+	return rewound.Tarfile()
+}
+
+// Find the named file (such as `triggers` or `postinst`) in the control.tar
+// and return its contents. The boolean return value is false if the file
+// is not present in the control.tar.
+func (deb *Deb) controlFile(name string) ([]byte, bool, error) {
+	archive, closer, err := deb.openTar("control.")
+	if err != nil {
+		return nil, false, err
+	}
+	defer closer.Close()
+
+	for {
+		member, err := archive.Next()
+		if err == io.EOF {
+			return nil, false, nil
+		} else if err != nil {
+			return nil, false, err
+		}
+		if path.Clean(member.Name) == name {
+			data, err := io.ReadAll(archive)
+			if err != nil {
+				return nil, false, err
+			}
+			return data, true, nil
+		}
+	}
+}
+
+// Find the regular file in the data.tar matching the earliest of the given
+// paths (such as `/usr/share/doc/hello/copyright`) which is present, and
+// return its path and contents. The paths are in order of preference, not
+// the order of the data.tar. If none of the paths are in the data.tar, the
+// returned path is empty, and the error is nil.
+func (deb *Deb) dataFile(names ...string) (string, []byte, error) {
+	archive, closer, err := deb.openTar("data.")
+	if err != nil {
+		return "", nil, err
+	}
+	defer closer.Close()
+
+	wanted := map[string]int{}
+	for i := len(names) - 1; i >= 0; i-- {
+		wanted[path.Clean("/"+names[i])] = i
+	}
+
+	found, foundData, best := "", []byte(nil), len(names)
+	for {
+		member, err := archive.Next()
+		if err == io.EOF {
+			return found, foundData, nil
+		} else if err != nil {
+			return "", nil, err
+		}
+		name := path.Clean("/" + member.Name)
+		rank, ok := wanted[name]
+		if member.Typeflag != tar.TypeReg || !ok || rank >= best {
+			continue
+		}
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return "", nil, err
+		}
+		if rank == 0 {
+			return name, data, nil
+		}
+		found, foundData, best = name, data, rank
+	}
+}
+
+// Member names {{{
+
+// Return the name of the control.tar member of the .deb, exactly as it is
+// in the ar(1) archive, such as `control.tar.xz` or `control.tar.zst`.
+func (deb *Deb) ControlTarName() (string, error) {
+	member, err := deb.findMember("control.")
+	if err != nil {
+		return "", err
+	}
+	return member.Name, nil
+}
+
+// Return the name of the data.tar member of the .deb, exactly as it is in
+// the ar(1) archive, such as `data.tar.gz` or `data.tar.zst`.
+func (deb *Deb) DataTarName() (string, error) {
+	member, err := deb.findMember("data.")
+	if err != nil {
+		return "", err
+	}
+	return member.Name, nil
+}
+
+// }}}
+
+// AllControlFiles {{{
+
+// Return the names of every file in the control.tar, in the order they're
+// stored in the archive, such as `control`, `md5sums`, `postinst` and
+// `templates`, without reading their contents. Names are cleaned of any
+// leading `./`, and the top level directory itself is not included.
+func (deb *Deb) AllControlFiles() ([]string, error) {
+	archive, closer, err := deb.openTar("control.")
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	ret := []string{}
+	for {
+		member, err := archive.Next()
+		if err == io.EOF {
+			return ret, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if name := path.Clean(member.Name); name != "." {
+			ret = append(ret, name)
+		}
+	}
+}
+
+// }}}
+
+// DataFileStat {{{
+
+// ErrFileNotFound is returned when looking up a path which isn't present in
+// the .deb.
+var ErrFileNotFound = errors.New("file not found in .deb")
+
+// Return the tar.Header of the file at the given path in the data.tar,
+// without reading the file's contents, which is handy for checking the
+// permissions or ownership of a file. The path is matched regardless of any
+// leading `./` or `/`, so `usr/bin/hello`, `/usr/bin/hello` and
+// `./usr/bin/hello` are all the same file. If the path isn't in the
+// data.tar, ErrFileNotFound is returned.
+func (deb *Deb) DataFileStat(name string) (*tar.Header, error) {
+	archive, closer, err := deb.openTar("data.")
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	name = path.Clean("/" + name)
+	for {
+		member, err := archive.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: '%s'", ErrFileNotFound, name)
+		} else if err != nil {
+			return nil, err
+		}
+		if path.Clean("/"+member.Name) == name {
+			return member, nil
+		}
+	}
+}
+
+// }}}
+
+// ReadFile {{{
+
+// ErrFileTooLarge is returned by ReadFile for a file bigger than the limit
+// set with SetReadFileLimit.
+var ErrFileTooLarge = errors.New("file too large to read from .deb")
+
+// The largest file ReadFile will read, unless changed with
+// SetReadFileLimit.
+const DefaultReadFileLimit = 64 << 20
+
+// Set the size (in bytes) of the largest file ReadFile will read, to keep
+// a hostile (or just huge) .deb from using up all the memory. A limit of
+// zero or less resets the limit to DefaultReadFileLimit.
+func (deb *Deb) SetReadFileLimit(limit int64) {
+	deb.readFileLimit = limit
+}
+
+// Return the contents of the file at the given path in the data.tar, such
+// as `/usr/share/doc/hello/copyright`, matching the path as DataFileStat
+// does. The data.tar is streamed through until the file is found, and only
+// the file itself is held in memory. Hard links are followed, but symbolic
+// links are not. If the path isn't in the data.tar, ErrFileNotFound is
+// returned, and if the file is bigger than the limit set with
+// SetReadFileLimit, ErrFileTooLarge is returned. A chain of hard links
+// which loops back on itself, or is longer than maxHardLinks, is an error.
+func (deb *Deb) ReadFile(name string) ([]byte, error) {
+	limit := deb.readFileLimit
+	if limit <= 0 {
+		limit = DefaultReadFileLimit
+	}
+
+	name = path.Clean("/" + name)
+	seen := map[string]bool{}
+	for {
+		if seen[name] {
+			return nil, fmt.Errorf("'%s' is part of a hard link loop", name)
+		}
+		if len(seen) > maxHardLinks {
+			return nil, fmt.Errorf("Too many hard links followed reading '%s'", name)
+		}
+		seen[name] = true
+
+		data, link, err := deb.readFile(name, limit)
+		if err != nil {
+			return nil, err
+		}
+		if link == "" {
+			return data, nil
+		}
+		name = link
+	}
+}
+
+// The most hard links ReadFile will follow before giving up.
+const maxHardLinks = 32
+
+// Read a single file out of the data.tar, without following hard links. If
+// the file is a hard link, the cleaned name of its target is returned
+// instead of its contents.
+func (deb *Deb) readFile(name string, limit int64) ([]byte, string, error) {
+	archive, closer, err := deb.openTar("data.")
+	if err != nil {
+		return nil, "", err
+	}
+	defer closer.Close()
+
+	for {
+		member, err := archive.Next()
+		if err == io.EOF {
+			return nil, "", fmt.Errorf("%w: '%s'", ErrFileNotFound, name)
+		} else if err != nil {
+			return nil, "", err
+		}
+		if path.Clean("/"+member.Name) != name {
+			continue
+		}
+
+		switch member.Typeflag {
+		case tar.TypeReg:
+		case tar.TypeLink:
+			return nil, path.Clean("/" + member.Linkname), nil
+		default:
+			return nil, "", fmt.Errorf("'%s' is not a regular file", name)
+		}
+		if member.Size > limit {
+			return nil, "", fmt.Errorf("%w: '%s' is %d bytes", ErrFileTooLarge, name, member.Size)
+		}
+		data := make([]byte, member.Size)
+		if _, err := io.ReadFull(archive, data); err != nil {
+			return nil, "", err
+		}
+		return data, "", nil
+	}
+}
+
+// }}}
+
+// InstalledSizeOnDisk {{{
+
+// SizeOptions control how InstalledSizeOnDiskWithOptions accounts for the
+// filesystem metadata of each entry in the data.tar.
+type SizeOptions struct {
+	// The number of bytes added for each directory.
+	DirectoryOverhead int64
+
+	// The number of bytes added for each entry which isn't a directory,
+	// such as a regular file or a symlink.
+	FileOverhead int64
+}
+
+// The SizeOptions used by InstalledSizeOnDisk, which count a block for
+// each directory, and nothing extra for files.
+var DefaultSizeOptions = SizeOptions{
+	DirectoryOverhead: 4096,
+	FileOverhead:      0,
+}
+
+// Return the number of bytes the package takes up once installed, by
+// summing the sizes of the regular files in the data.tar, with the
+// overhead of DefaultSizeOptions. This is usually a better estimate than
+// the Installed-Size field, which is only a rough guess made at build time.
+func (deb *Deb) InstalledSizeOnDisk() (int64, error) {
+	return deb.InstalledSizeOnDiskWithOptions(DefaultSizeOptions)
+}
+
+// Return the number of bytes the package takes up once installed, as
+// with InstalledSizeOnDisk, with the given SizeOptions.
+func (deb *Deb) InstalledSizeOnDiskWithOptions(options SizeOptions) (int64, error) {
+	archive, closer, err := deb.openTar("data.")
+	if err != nil {
+		return 0, err
+	}
+	defer closer.Close()
+
+	var size int64
+	for {
+		member, err := archive.Next()
+		if err == io.EOF {
+			return size, nil
+		} else if err != nil {
+			return 0, err
+		}
+		switch member.Typeflag {
+		case tar.TypeDir:
+			size += options.DirectoryOverhead
+		case tar.TypeReg:
+			size += member.Size + options.FileOverhead
+		default:
+			size += options.FileOverhead
+		}
+	}
+}
+
+// }}}
+
+// RawControlTar {{{
+
+// Return the control.tar member of the .deb, exactly as it's stored in the
+// archive (without decompressing it), along with the compression format of
+// that member, such as `gz`, `xz` or `zst`. The format is an empty string
+// if the control.tar is not compressed at all.
+func (deb *Deb) RawControlTar() ([]byte, string, error) {
+	member, err := deb.findMember("control.")
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := io.ReadAll(io.NewSectionReader(member.Data, 0, member.Size))
+	if err != nil {
+		return nil, "", err
+	}
+	return data, compressionOf(member.Name[8:]), nil
+}
+
+// }}}
+
+// DataTarCompression {{{
+
+// Return the CompressionFormat of the data.tar member of the .deb, as given
+// by the suffix of the member name. Unlike reading Data, this doesn't
+// decompress anything, which is handy for callers that want to handle the
+// data.tar themselves.
+func (deb *Deb) DataTarCompression() (CompressionFormat, error) {
+	member, err := deb.findMember("data.")
+	if err != nil {
+		return CompressionNone, err
+	}
+	if !member.IsTarfile() {
+		return CompressionNone, fmt.Errorf("%s appears to not be a tarfile", member.Name)
+	}
+	format := CompressionFormat(compressionOf(member.Name[5:]))
+	if format == CompressionNone {
+		return format, nil
+	}
+	if _, ok := knownCompressionAlgorithms["."+string(format)]; !ok {
+		return CompressionNone, fmt.Errorf("Unknown compression format: '%s'", format)
+	}
+	return format, nil
+}
+
+// }}}
+
+// }}}
+
+// vim: foldmethod=marker
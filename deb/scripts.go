@@ -0,0 +1,61 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+// Return the contents of the named maintainer script in the control.tar,
+// or nil if the package doesn't ship it.
+func (deb *Deb) maintainerScript(name string) ([]byte, error) {
+	data, ok, err := deb.controlFile(name)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Return the contents of the package's `postinst` maintainer script, which
+// is run after the package is unpacked. If the package has no `postinst`,
+// this returns nil without an error.
+func (deb *Deb) PostinstScript() ([]byte, error) {
+	return deb.maintainerScript("postinst")
+}
+
+// Return the contents of the package's `preinst` maintainer script, which
+// is run before the package is unpacked. If the package has no `preinst`,
+// this returns nil without an error.
+func (deb *Deb) PreinstScript() ([]byte, error) {
+	return deb.maintainerScript("preinst")
+}
+
+// Return the contents of the package's `prerm` maintainer script, which is
+// run before the package is removed. If the package has no `prerm`, this
+// returns nil without an error.
+func (deb *Deb) PrermScript() ([]byte, error) {
+	return deb.maintainerScript("prerm")
+}
+
+// Return the contents of the package's `postrm` maintainer script, which
+// is run after the package is removed (or purged). If the package has no
+// `postrm`, this returns nil without an error.
+func (deb *Deb) PostrmScript() ([]byte, error) {
+	return deb.maintainerScript("postrm")
+}
+
+// vim: foldmethod=marker
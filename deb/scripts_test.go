@@ -0,0 +1,35 @@
+package deb_test
+
+import (
+	"testing"
+)
+
+/*
+ *
+ */
+
+func TestMaintainerScripts(t *testing.T) {
+	const postinst = "#!/bin/sh\nset -e\nldconfig\n"
+	const prerm = "#!/bin/sh\nset -e\n"
+	debFile, _ := buildDeb(t, testControl, []testFile{
+		{Name: "./postinst", Body: postinst, Mode: 0755},
+		{Name: "./prerm", Body: prerm, Mode: 0755},
+	}, nil)
+	defer debFile.Close()
+
+	script, err := debFile.PostinstScript()
+	isok(t, err)
+	assert(t, string(script) == postinst)
+	script, err = debFile.PrermScript()
+	isok(t, err)
+	assert(t, string(script) == prerm)
+
+	script, err = debFile.PreinstScript()
+	isok(t, err)
+	assert(t, script == nil)
+	script, err = debFile.PostrmScript()
+	isok(t, err)
+	assert(t, script == nil)
+}
+
+// vim: foldmethod=marker
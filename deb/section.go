@@ -0,0 +1,194 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Section {{{
+
+// Section is the typed form of the Section field, which classifies the
+// package by application area, as defined in Debian Policy, section 2.4.
+// A Section never contains the archive area (such as `contrib/`) that may
+// prefix it in the control file.
+type Section string
+
+const (
+	SectionAdmin           Section = "admin"
+	SectionCliMono         Section = "cli-mono"
+	SectionComm            Section = "comm"
+	SectionDatabase        Section = "database"
+	SectionDebianInstaller Section = "debian-installer"
+	SectionDebug           Section = "debug"
+	SectionDevel           Section = "devel"
+	SectionDoc             Section = "doc"
+	SectionEditors         Section = "editors"
+	SectionEducation       Section = "education"
+	SectionElectronics     Section = "electronics"
+	SectionEmbedded        Section = "embedded"
+	SectionFonts           Section = "fonts"
+	SectionGames           Section = "games"
+	SectionGnome           Section = "gnome"
+	SectionGnuR            Section = "gnu-r"
+	SectionGnustep         Section = "gnustep"
+	SectionGraphics        Section = "graphics"
+	SectionHamradio        Section = "hamradio"
+	SectionHaskell         Section = "haskell"
+	SectionHttpd           Section = "httpd"
+	SectionInterpreters    Section = "interpreters"
+	SectionIntrospection   Section = "introspection"
+	SectionJava            Section = "java"
+	SectionJavascript      Section = "javascript"
+	SectionKde             Section = "kde"
+	SectionKernel          Section = "kernel"
+	SectionLibdevel        Section = "libdevel"
+	SectionLibs            Section = "libs"
+	SectionLisp            Section = "lisp"
+	SectionLocalization    Section = "localization"
+	SectionMail            Section = "mail"
+	SectionMath            Section = "math"
+	SectionMetapackages    Section = "metapackages"
+	SectionMisc            Section = "misc"
+	SectionNet             Section = "net"
+	SectionNews            Section = "news"
+	SectionOcaml           Section = "ocaml"
+	SectionOldlibs         Section = "oldlibs"
+	SectionOtherosfs       Section = "otherosfs"
+	SectionPerl            Section = "perl"
+	SectionPhp             Section = "php"
+	SectionPython          Section = "python"
+	SectionRuby            Section = "ruby"
+	SectionRust            Section = "rust"
+	SectionScience         Section = "science"
+	SectionShells          Section = "shells"
+	SectionSound           Section = "sound"
+	SectionTasks           Section = "tasks"
+	SectionTex             Section = "tex"
+	SectionText            Section = "text"
+	SectionUtils           Section = "utils"
+	SectionVcs             Section = "vcs"
+	SectionVideo           Section = "video"
+	SectionWeb             Section = "web"
+	SectionX11             Section = "x11"
+	SectionXfce            Section = "xfce"
+	SectionZope            Section = "zope"
+)
+
+var knownSections = map[Section]bool{
+	SectionAdmin: true, SectionCliMono: true, SectionComm: true,
+	SectionDatabase: true, SectionDebianInstaller: true, SectionDebug: true,
+	SectionDevel: true, SectionDoc: true, SectionEditors: true,
+	SectionEducation: true, SectionElectronics: true, SectionEmbedded: true,
+	SectionFonts: true, SectionGames: true, SectionGnome: true,
+	SectionGnuR: true, SectionGnustep: true, SectionGraphics: true,
+	SectionHamradio: true, SectionHaskell: true, SectionHttpd: true,
+	SectionInterpreters: true, SectionIntrospection: true, SectionJava: true,
+	SectionJavascript: true, SectionKde: true, SectionKernel: true,
+	SectionLibdevel: true, SectionLibs: true, SectionLisp: true,
+	SectionLocalization: true, SectionMail: true, SectionMath: true,
+	SectionMetapackages: true, SectionMisc: true, SectionNet: true,
+	SectionNews: true, SectionOcaml: true, SectionOldlibs: true,
+	SectionOtherosfs: true, SectionPerl: true, SectionPhp: true,
+	SectionPython: true, SectionRuby: true, SectionRust: true,
+	SectionScience: true, SectionShells: true, SectionSound: true,
+	SectionTasks: true, SectionTex: true, SectionText: true,
+	SectionUtils: true, SectionVcs: true, SectionVideo: true,
+	SectionWeb: true, SectionX11: true, SectionXfce: true,
+	SectionZope: true,
+}
+
+// Return the Section of the package, with any archive area prefix (such
+// as `non-free/`) removed. An error is returned if the package has no
+// Section, or if the Section isn't one defined by Debian Policy.
+func (deb *Deb) Section() (Section, error) {
+	section := strings.TrimSpace(deb.Control.Section)
+	if i := strings.LastIndex(section, "/"); i != -1 {
+		section = section[i+1:]
+	}
+	if section == "" {
+		return "", fmt.Errorf("Package '%s' has no Section", deb.Control.Package)
+	}
+	if !knownSections[Section(section)] {
+		return "", fmt.Errorf("Unknown section: '%s'", deb.Control.Section)
+	}
+	return Section(section), nil
+}
+
+// }}}
+
+// Priority {{{
+
+// Priority is the typed form of the Priority field, as defined in Debian
+// Policy, section 2.5. Priorities are ordered from most to least important.
+type Priority int
+
+const (
+	PriorityRequired Priority = iota
+	PriorityImportant
+	PriorityStandard
+	PriorityOptional
+
+	// The `extra` priority is deprecated in favor of `optional`, but is
+	// still found in older packages.
+	PriorityExtra
+)
+
+var priorityNames = map[Priority]string{
+	PriorityRequired:  "required",
+	PriorityImportant: "important",
+	PriorityStandard:  "standard",
+	PriorityOptional:  "optional",
+	PriorityExtra:     "extra",
+}
+
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// Check to see if packages of this Priority are required for the proper
+// functioning of the system.
+func (p Priority) IsRequired() bool {
+	return p == PriorityRequired
+}
+
+// Return the Priority of the package. An error is returned if the package
+// has no Priority, or if it isn't one defined by Debian Policy.
+func (deb *Deb) Priority() (Priority, error) {
+	priority := strings.TrimSpace(deb.Control.Priority)
+	if priority == "" {
+		return PriorityOptional, fmt.Errorf("Package '%s' has no Priority", deb.Control.Package)
+	}
+	for p, name := range priorityNames {
+		if name == priority {
+			return p, nil
+		}
+	}
+	return PriorityOptional, fmt.Errorf("Unknown priority: '%s'", deb.Control.Priority)
+}
+
+// }}}
+
+// vim: foldmethod=marker
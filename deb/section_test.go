@@ -0,0 +1,56 @@
+package deb_test
+
+import (
+	"testing"
+
+	"github.com/akozlenkov/go-debian/deb"
+)
+
+/*
+ *
+ */
+
+func TestSection(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	section, err := debFile.Section()
+	isok(t, err)
+	assert(t, section == deb.SectionDevel)
+
+	debFile.Control.Section = "non-free/libs"
+	section, err = debFile.Section()
+	isok(t, err)
+	assert(t, section == deb.SectionLibs)
+
+	for _, bad := range []string{"", "contrib/", "frobnicate"} {
+		debFile.Control.Section = bad
+		_, err = debFile.Section()
+		notok(t, err)
+	}
+}
+
+func TestPriority(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	priority, err := debFile.Priority()
+	isok(t, err)
+	assert(t, priority == deb.PriorityOptional)
+	assert(t, priority.String() == "optional")
+	assert(t, !priority.IsRequired())
+
+	debFile.Control.Priority = "required"
+	priority, err = debFile.Priority()
+	isok(t, err)
+	assert(t, priority.IsRequired())
+	assert(t, priority < deb.PriorityImportant)
+
+	for _, bad := range []string{"", "Required", "low"} {
+		debFile.Control.Priority = bad
+		_, err = debFile.Priority()
+		notok(t, err)
+	}
+}
+
+// vim: foldmethod=marker
@@ -1,6 +1,7 @@
 package deb // import "github.com/akozlenkov/go-debian/deb"
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -10,16 +11,55 @@ import (
 
 const (
 	SigTypeArchive = `archive`
+	SigTypeBuilder = `builder`
 	SigTypeMaint   = `maint`
 	SigTypeOrigin  = `origin`
 )
 
+// ErrNoSignature is returned when a .deb carries no debsig signature
+// members at all.
+var ErrNoSignature = errors.New("no debsig signature present")
+
+// The signature types checked by VerifyDebsigSignature, in order of
+// preference.
+var debsigPreference = []string{
+	SigTypeOrigin,
+	SigTypeBuilder,
+	SigTypeMaint,
+	SigTypeArchive,
+}
+
 func (deb *Deb) CheckDebsig(validKeys openpgp.EntityList, sigType string) (signer *openpgp.Entity, err error) {
 	sig, ok := deb.ArContent[`_gpg`+sigType]
 	if !ok {
 		return nil, fmt.Errorf("no signature of type %v present", sigType)
 	}
+	return deb.checkDebsig(validKeys, sig)
+}
+
+// Verify the debsig-style signature of the .deb against the given keyring,
+// returning the Entity that signed it. If more than one signature member is
+// present, `_gpgorigin` is preferred, followed by `_gpgbuilder`,
+// `_gpgmaint` and `_gpgarchive`. If the .deb has no signature members,
+// ErrNoSignature is returned.
+func (deb *Deb) VerifyDebsigSignature(keyring openpgp.KeyRing) (*openpgp.Entity, error) {
+	for _, sigType := range debsigPreference {
+		if sig, ok := deb.ArContent[`_gpg`+sigType]; ok {
+			return deb.checkDebsig(keyring, sig)
+		}
+	}
+	for name := range deb.ArContent {
+		if strings.HasPrefix(name, "_gpg") {
+			return nil, fmt.Errorf("unknown signature type %v present", name[4:])
+		}
+	}
+	return nil, ErrNoSignature
+}
 
+// Check the detached signature sig over the signed members of the .deb,
+// which are the debian-binary flag followed by the control and data
+// members.
+func (deb *Deb) checkDebsig(keyring openpgp.KeyRing, sig *ArEntry) (*openpgp.Entity, error) {
 	binaryFlag, ok := deb.ArContent[`debian-binary`]
 	if !ok {
 		return nil, fmt.Errorf("archive does not contain a debian-binary flag")
@@ -45,6 +85,7 @@ func (deb *Deb) CheckDebsig(validKeys openpgp.EntityList, sigType string) (signe
 	binaryFlag.Data.Seek(0, 0)
 	control.Data.Seek(0, 0)
 	data.Data.Seek(0, 0)
+	sig.Data.Seek(0, 0)
 	signedData := io.MultiReader(binaryFlag.Data, control.Data, data.Data)
-	return openpgp.CheckDetachedSignature(validKeys, signedData, sig.Data)
+	return openpgp.CheckDetachedSignature(keyring, signedData, sig.Data)
 }
@@ -0,0 +1,80 @@
+package deb_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/deb"
+	"golang.org/x/crypto/openpgp"
+)
+
+/*
+ *
+ */
+
+func loadTestKeyring(t *testing.T, name string) openpgp.EntityList {
+	t.Helper()
+	f, err := os.Open("testdata/keyrings/FAD46790DE88C7E2/" + name)
+	isok(t, err)
+	defer f.Close()
+	keyring, err := openpgp.ReadKeyRing(f)
+	isok(t, err)
+	return keyring
+}
+
+// Build a .deb signed with the test key, with the signature placed in a
+// member of the given name.
+func buildSignedDeb(t *testing.T, sigMember string) *deb.Deb {
+	t.Helper()
+	secring := loadTestKeyring(t, "secring.gpg")
+
+	binaryFlag := "2.0\n"
+	controlTar := string(buildTarGz(t, []testFile{{Name: "./control", Body: testControl}}))
+	dataTar := string(buildTarGz(t, nil))
+
+	sig := bytes.Buffer{}
+	isok(t, openpgp.DetachSign(&sig, secring[0],
+		bytes.NewReader([]byte(binaryFlag+controlTar+dataTar)), nil))
+
+	debFile, err := deb.Load(bytes.NewReader(buildAr(
+		[2]string{"debian-binary", binaryFlag},
+		[2]string{"control.tar.gz", controlTar},
+		[2]string{"data.tar.gz", dataTar},
+		[2]string{sigMember, sig.String()},
+	)), "hello_2.10-2_amd64.deb")
+	isok(t, err)
+	return debFile
+}
+
+func TestVerifyDebsigSignature(t *testing.T) {
+	pubring := loadTestKeyring(t, "pubring.gpg")
+
+	for _, member := range []string{"_gpgorigin", "_gpgbuilder"} {
+		debFile := buildSignedDeb(t, member)
+		signer, err := debFile.VerifyDebsigSignature(pubring)
+		isok(t, err)
+		assert(t, signer.PrimaryKey.KeyIdString() == "FAD46790DE88C7E2")
+		debFile.Close()
+	}
+
+	debFile := buildSignedDeb(t, "_gpgorigin")
+	defer debFile.Close()
+	_, err := debFile.VerifyDebsigSignature(openpgp.EntityList{})
+	notok(t, err)
+
+	signer, err := debFile.CheckDebsig(pubring, deb.SigTypeOrigin)
+	isok(t, err)
+	assert(t, signer.PrimaryKey.KeyIdString() == "FAD46790DE88C7E2")
+}
+
+func TestVerifyDebsigNoSignature(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	_, err := debFile.VerifyDebsigSignature(loadTestKeyring(t, "pubring.gpg"))
+	assert(t, errors.Is(err, deb.ErrNoSignature))
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,69 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+import (
+	"github.com/akozlenkov/go-debian/control"
+)
+
+// Return the Debtags (such as `role::program` or `implemented-in::go`) of
+// the package, as listed in the `Tag` field of the control file. If the
+// package has no tags, an empty list is returned.
+func (deb *Deb) Tags() ([]string, error) {
+	return deb.Control.GetCommaList("Tag"), nil
+}
+
+// Return the Debtags of a Packages index entry. The `Tag` field is used if
+// the entry was read from a control file, otherwise the Tags member is.
+func binaryIndexTags(pkg *control.BinaryIndex) []string {
+	if _, ok := pkg.Values["Tag"]; ok {
+		return pkg.GetCommaList("Tag")
+	}
+	return pkg.Tags
+}
+
+// Return the Packages index entries which are tagged with the given Debtag.
+func FilterByTag(pkgs []*control.BinaryIndex, tag string) []*control.BinaryIndex {
+	ret := []*control.BinaryIndex{}
+	for _, pkg := range pkgs {
+		for _, el := range binaryIndexTags(pkg) {
+			if el == tag {
+				ret = append(ret, pkg)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// Return the number of Packages index entries tagged with each Debtag
+// used by any of the given entries.
+func AllTags(pkgs []*control.BinaryIndex) map[string]int {
+	ret := map[string]int{}
+	for _, pkg := range pkgs {
+		for _, tag := range binaryIndexTags(pkg) {
+			ret[tag]++
+		}
+	}
+	return ret
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,68 @@
+package deb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/deb"
+)
+
+/*
+ *
+ */
+
+func TestTags(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl+"Tag: devel::lang:c, role::program,\n implemented-in::c\n", nil, nil)
+	defer debFile.Close()
+
+	tags, err := debFile.Tags()
+	isok(t, err)
+	assert(t, len(tags) == 3)
+	assert(t, tags[2] == "implemented-in::c")
+
+	debFile, _ = buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+	tags, err = debFile.Tags()
+	isok(t, err)
+	assert(t, len(tags) == 0)
+}
+
+func TestFilterByTag(t *testing.T) {
+	decoder, err := control.NewDecoder(strings.NewReader(`Package: hello
+Tag: devel::lang:c, role::program
+
+Package: golang-go
+Tag: devel::compiler, implemented-in::go, role::program
+
+Package: fbautostart
+`), nil)
+	isok(t, err)
+	pkgs := []*control.BinaryIndex{}
+	for i := 0; i < 3; i++ {
+		pkg := control.BinaryIndex{}
+		isok(t, decoder.Decode(&pkg))
+		pkgs = append(pkgs, &pkg)
+	}
+	pkgs = append(pkgs, &control.BinaryIndex{
+		Package: "delve",
+		Tags:    []string{"devel::debugger", "implemented-in::go"},
+	})
+
+	programs := deb.FilterByTag(pkgs, "role::program")
+	assert(t, len(programs) == 2)
+	assert(t, programs[1].Package == "golang-go")
+
+	golang := deb.FilterByTag(pkgs, "implemented-in::go")
+	assert(t, len(golang) == 2)
+	assert(t, golang[1].Package == "delve")
+
+	assert(t, len(deb.FilterByTag(pkgs, "role::shared-lib")) == 0)
+
+	tags := deb.AllTags(pkgs)
+	assert(t, len(tags) == 5)
+	assert(t, tags["role::program"] == 2)
+	assert(t, tags["devel::compiler"] == 1)
+}
+
+// vim: foldmethod=marker
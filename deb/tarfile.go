@@ -104,6 +104,28 @@ func SetXZMaxDict(maxDict uint32) {
 
 // }}}
 
+// CompressionFormat {{{
+
+// CompressionFormat is the compression applied to a tar member of a .deb,
+// named after the file extension it's stored with.
+type CompressionFormat string
+
+const (
+	CompressionNone  CompressionFormat = ""
+	CompressionGzip  CompressionFormat = "gz"
+	CompressionBzip2 CompressionFormat = "bz2"
+	CompressionXZ    CompressionFormat = "xz"
+	CompressionLZMA  CompressionFormat = "lzma"
+	CompressionZstd  CompressionFormat = "zst"
+)
+
+// Return the DecompressorFunc for this CompressionFormat.
+func (c CompressionFormat) Decompressor() DecompressorFunc {
+	return DecompressorFor("." + string(c))
+}
+
+// }}}
+
 // IsTarfile {{{
 
 // Check to see if the given ArEntry is, in fact, a Tarfile. This method
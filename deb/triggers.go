@@ -0,0 +1,140 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package deb // import "github.com/akozlenkov/go-debian/deb"
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A TriggerDeclaration is a single directive of the `triggers` control
+// file, as described in deb-triggers(5). The Type is one of `interest`,
+// `interest-await`, `interest-noawait`, `activate`, `activate-await` or
+// `activate-noawait`, and the Path is the name of the trigger, which is
+// either a filesystem path (file triggers) or an explicit trigger name.
+type TriggerDeclaration struct {
+	Type string
+	Path string
+}
+
+// Check to see if this declaration is one of the `interest` directives.
+func (t TriggerDeclaration) IsInterest() bool {
+	return strings.HasPrefix(t.Type, "interest")
+}
+
+// Check to see if this declaration is one of the `activate` directives.
+func (t TriggerDeclaration) IsActivation() bool {
+	return strings.HasPrefix(t.Type, "activate")
+}
+
+var knownTriggerTypes = map[string]bool{
+	"interest":         true,
+	"interest-await":   true,
+	"interest-noawait": true,
+	"activate":         true,
+	"activate-await":   true,
+	"activate-noawait": true,
+}
+
+// Parse the `triggers` control file into a list of TriggerDeclarations.
+func parseTriggers(data []byte) ([]TriggerDeclaration, error) {
+	ret := []TriggerDeclaration{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		els := strings.Fields(line)
+		if len(els) != 2 || !knownTriggerTypes[els[0]] {
+			return nil, fmt.Errorf("Bad triggers line: '%s'", line)
+		}
+		ret = append(ret, TriggerDeclaration{Type: els[0], Path: els[1]})
+	}
+	return ret, scanner.Err()
+}
+
+// Return all the TriggerDeclarations of the `triggers` control file, in the
+// order they're declared in. If the package has no `triggers` control file,
+// an empty list is returned.
+func (deb *Deb) Triggers() ([]TriggerDeclaration, error) {
+	data, ok, err := deb.controlFile("triggers")
+	if err != nil || !ok {
+		return []TriggerDeclaration{}, err
+	}
+	return parseTriggers(data)
+}
+
+func (deb *Deb) filterTriggers(keep func(TriggerDeclaration) bool) ([]TriggerDeclaration, error) {
+	triggers, err := deb.Triggers()
+	if err != nil {
+		return nil, err
+	}
+	ret := []TriggerDeclaration{}
+	for _, trigger := range triggers {
+		if keep(trigger) {
+			ret = append(ret, trigger)
+		}
+	}
+	return ret, nil
+}
+
+// Return the TriggerDeclarations of the `triggers` control file which
+// declare an interest in a trigger.
+func (deb *Deb) TriggerInterests() ([]TriggerDeclaration, error) {
+	return deb.filterTriggers(TriggerDeclaration.IsInterest)
+}
+
+// Return the TriggerDeclarations of the `triggers` control file which
+// activate a trigger.
+func (deb *Deb) TriggerActivations() ([]TriggerDeclaration, error) {
+	return deb.filterTriggers(TriggerDeclaration.IsActivation)
+}
+
+func (deb *Deb) hasTrigger(keep func(TriggerDeclaration) bool, name string) (bool, error) {
+	triggers, err := deb.filterTriggers(keep)
+	if err != nil {
+		return false, err
+	}
+	for _, trigger := range triggers {
+		if trigger.Path == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Check to see if the package declares an interest of any type in the
+// given trigger, which is either a file trigger path or an explicit
+// trigger name.
+func (deb *Deb) HasInterestInTrigger(name string) (bool, error) {
+	return deb.hasTrigger(TriggerDeclaration.IsInterest, name)
+}
+
+// Check to see if the package activates the given trigger, with any type
+// of `activate` directive.
+func (deb *Deb) ActivatesTrigger(name string) (bool, error) {
+	return deb.hasTrigger(TriggerDeclaration.IsActivation, name)
+}
+
+// vim: foldmethod=marker
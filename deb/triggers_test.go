@@ -0,0 +1,74 @@
+package deb_test
+
+import (
+	"testing"
+)
+
+/*
+ *
+ */
+
+func TestTriggers(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, []testFile{
+		{Name: "./triggers", Body: `# Triggers for hello
+interest-noawait /usr/share/hello
+activate-noawait ldconfig
+
+interest update-hello
+`},
+	}, nil)
+	defer debFile.Close()
+
+	triggers, err := debFile.Triggers()
+	isok(t, err)
+	assert(t, len(triggers) == 3)
+	assert(t, triggers[1].Type == "activate-noawait")
+	assert(t, triggers[1].Path == "ldconfig")
+
+	interests, err := debFile.TriggerInterests()
+	isok(t, err)
+	assert(t, len(interests) == 2)
+	assert(t, interests[0].Type == "interest-noawait")
+	assert(t, interests[0].Path == "/usr/share/hello")
+	assert(t, interests[1].Path == "update-hello")
+
+	activations, err := debFile.TriggerActivations()
+	isok(t, err)
+	assert(t, len(activations) == 1)
+	assert(t, activations[0].Path == "ldconfig")
+
+	for name, expected := range map[string][2]bool{
+		"/usr/share/hello": {true, false},
+		"update-hello":     {true, false},
+		"ldconfig":         {false, true},
+		"/usr/share":       {false, false},
+	} {
+		interested, err := debFile.HasInterestInTrigger(name)
+		isok(t, err)
+		assert(t, interested == expected[0])
+		activates, err := debFile.ActivatesTrigger(name)
+		isok(t, err)
+		assert(t, activates == expected[1])
+	}
+}
+
+func TestNoTriggers(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, nil, nil)
+	defer debFile.Close()
+
+	triggers, err := debFile.Triggers()
+	isok(t, err)
+	assert(t, len(triggers) == 0)
+}
+
+func TestBadTriggers(t *testing.T) {
+	debFile, _ := buildDeb(t, testControl, []testFile{
+		{Name: "./triggers", Body: "frobnicate /usr/share/hello\n"},
+	}, nil)
+	defer debFile.Close()
+
+	_, err := debFile.Triggers()
+	notok(t, err)
+}
+
+// vim: foldmethod=marker
@@ -166,4 +166,86 @@ func (arch *Arch) Is(other *Arch) bool {
 	return false
 }
 
+// kernelCPUs maps each Debian CPU name to the CPU name of the kernel that
+// runs its binaries, so that architectures sharing a kernel (such as amd64,
+// i386 and x32 on an x86_64 kernel) can be found.
+var kernelCPUs = map[string]string{
+	"amd64": "x86_64",
+	"i386":  "x86_64",
+	"x32":   "x86_64",
+
+	"arm64": "aarch64",
+	"armhf": "aarch64",
+	"armel": "aarch64",
+
+	// Big and little endian CPUs can't share a kernel.
+	"ppc64el": "ppc64le",
+	"ppc64":   "ppc64",
+	"powerpc": "ppc64",
+
+	"s390x": "s390x",
+	"s390":  "s390x",
+
+	"mips64el": "mips64el",
+	"mipsel":   "mips64el",
+	"mips64":   "mips64",
+	"mips":     "mips64",
+
+	"sparc64": "sparc64",
+	"sparc":   "sparc64",
+
+	"riscv64": "riscv64",
+	"loong64": "loong64",
+	"alpha":   "alpha",
+	"hppa":    "hppa",
+	"ia64":    "ia64",
+	"m68k":    "m68k",
+	"sh4":     "sh4",
+}
+
+// Check to see if the Arch is `all`, or a concrete (non-wildcard)
+// architecture with a CPU known to Debian, such as amd64 or armhf.
+func (arch *Arch) IsKnown() bool {
+	if arch.CPU == "all" {
+		return arch.OS == "all" && arch.ABI == "all"
+	}
+	if arch.IsWildcard() {
+		return false
+	}
+	_, ok := kernelCPUs[arch.CPU]
+	return ok
+}
+
+// Return all the pairs of distinct architectures from the given list which
+// can run side by side on the same kernel, such as amd64 and i386, which
+// both run on an x86_64 Linux kernel. Each pair is returned once, in the
+// order the architectures were given. Wildcard architectures, and the `all`
+// architecture, are never part of a pair.
+//
+// This is not the same as checking whether packages for both architectures
+// may be co-installed with Multi-Arch; rather, it's the set of
+// architectures a cross-building environment may be set up for.
+func CompatiblePairs(arches []Arch) [][2]Arch {
+	ret := [][2]Arch{}
+	for i, a := range arches {
+		for _, b := range arches[i+1:] {
+			if a != b && a.sharesKernelWith(&b) {
+				ret = append(ret, [2]Arch{a, b})
+			}
+		}
+	}
+	return ret
+}
+
+func (arch *Arch) sharesKernelWith(other *Arch) bool {
+	if arch.IsWildcard() || other.IsWildcard() || arch.CPU == "all" || other.CPU == "all" {
+		return false
+	}
+	if arch.OS != other.OS {
+		return false
+	}
+	kernel, ok := kernelCPUs[arch.CPU]
+	return ok && kernel == kernelCPUs[other.CPU]
+}
+
 // vim: foldmethod=marker
@@ -21,6 +21,7 @@
 package dependency_test
 
 import (
+	"runtime"
 	"testing"
 
 	"github.com/akozlenkov/go-debian/dependency"
@@ -119,4 +120,55 @@ func TestArchSetCompare(t *testing.T) {
 	assert(t, barArch.Matches(iAmNot))
 }
 
+func TestNativeArch(t *testing.T) {
+	arch := dependency.NativeArch()
+	assert(t, arch.CPU == dependency.CPUName())
+	assert(t, arch.Is(&dependency.Any))
+
+	if runtime.GOOS == "linux" && runtime.GOARCH == "amd64" {
+		assert(t, dependency.KernelName() == "linux")
+		assert(t, dependency.CPUName() == "amd64")
+		amd64, err := dependency.ParseArch("amd64")
+		isok(t, err)
+		assert(t, arch == *amd64)
+	}
+}
+
+func TestCompatiblePairs(t *testing.T) {
+	arches, err := dependency.ParseArchitectures("amd64 arm64 i386 any all armhf kfreebsd-i386 x32 riscv64")
+	isok(t, err)
+
+	pairs := dependency.CompatiblePairs(arches)
+	assert(t, len(pairs) == 4)
+	assert(t, pairs[0][0].CPU == "amd64" && pairs[0][1].CPU == "i386")
+	assert(t, pairs[1][0].CPU == "amd64" && pairs[1][1].CPU == "x32")
+	assert(t, pairs[2][0].CPU == "arm64" && pairs[2][1].CPU == "armhf")
+	assert(t, pairs[3][0].CPU == "i386" && pairs[3][1].CPU == "x32")
+
+	assert(t, len(dependency.CompatiblePairs(arches[:1])) == 0)
+	assert(t, len(dependency.CompatiblePairs([]dependency.Arch{arches[0], arches[0]})) == 0)
+
+	// Big and little endian CPUs never share a kernel.
+	arches, err = dependency.ParseArchitectures("mips mipsel mips64 mips64el ppc64el ppc64 powerpc")
+	isok(t, err)
+	pairs = dependency.CompatiblePairs(arches)
+	assert(t, len(pairs) == 3)
+	assert(t, pairs[0][0].CPU == "mips" && pairs[0][1].CPU == "mips64")
+	assert(t, pairs[1][0].CPU == "mipsel" && pairs[1][1].CPU == "mips64el")
+	assert(t, pairs[2][0].CPU == "ppc64" && pairs[2][1].CPU == "powerpc")
+}
+
+func TestArchIsKnown(t *testing.T) {
+	for _, name := range []string{"amd64", "all", "armhf", "riscv64", "mipsel", "ppc64el", "powerpc"} {
+		arch, err := dependency.ParseArch(name)
+		isok(t, err)
+		assert(t, arch.IsKnown())
+	}
+	for _, name := range []string{"any", "linux-any", "any-amd64", "z80"} {
+		arch, err := dependency.ParseArch(name)
+		isok(t, err)
+		assert(t, !arch.IsKnown())
+	}
+}
+
 // vim: foldmethod=marker
@@ -0,0 +1,92 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package dependency // import "github.com/akozlenkov/go-debian/dependency"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MultiArch models the value of the Multi-Arch field of a binary package,
+// which describes how the package behaves when more than one architecture
+// is configured on the same system.
+type MultiArch int
+
+const (
+	MultiArchNo MultiArch = iota
+	MultiArchSame
+	MultiArchForeign
+	MultiArchAllowed
+)
+
+var multiArchNames = map[MultiArch]string{
+	MultiArchNo:      "no",
+	MultiArchSame:    "same",
+	MultiArchForeign: "foreign",
+	MultiArchAllowed: "allowed",
+}
+
+// Parse the value of a Multi-Arch field. An empty value is treated as
+// `no`, which is the default when the field is absent.
+func ParseMultiArch(in string) (MultiArch, error) {
+	value := strings.ToLower(strings.TrimSpace(in))
+	if value == "" {
+		return MultiArchNo, nil
+	}
+	for multiArch, name := range multiArchNames {
+		if name == value {
+			return multiArch, nil
+		}
+	}
+	return MultiArchNo, fmt.Errorf("Unknown Multi-Arch value: '%s'", in)
+}
+
+func (m MultiArch) String() string {
+	if name, ok := multiArchNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("MultiArch(%d)", int(m))
+}
+
+func (m *MultiArch) UnmarshalControl(data string) error {
+	var err error
+	*m, err = ParseMultiArch(data)
+	return err
+}
+
+func (m MultiArch) MarshalControl() (string, error) {
+	return m.String(), nil
+}
+
+// Check if instances of this package for different architectures may be
+// installed at the same time, which is only the case for `same`.
+func (m MultiArch) AllowsCoinstallation() bool {
+	return m == MultiArchSame
+}
+
+// Check if a dependency on this package may be satisfied by an instance
+// of a different architecture, which is the case for `foreign` (always)
+// and `allowed` (when the dependency is annotated with `:any`).
+func (m MultiArch) IsSatisfiedByForeign() bool {
+	return m == MultiArchForeign || m == MultiArchAllowed
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,64 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package dependency_test
+
+import (
+	"testing"
+
+	"github.com/akozlenkov/go-debian/dependency"
+)
+
+/*
+ *
+ */
+
+func TestParseMultiArch(t *testing.T) {
+	for value, expected := range map[string]dependency.MultiArch{
+		"":        dependency.MultiArchNo,
+		"no":      dependency.MultiArchNo,
+		"same":    dependency.MultiArchSame,
+		"Foreign": dependency.MultiArchForeign,
+		"allowed": dependency.MultiArchAllowed,
+	} {
+		multiArch, err := dependency.ParseMultiArch(value)
+		isok(t, err)
+		assert(t, multiArch == expected)
+	}
+
+	_, err := dependency.ParseMultiArch("sometimes")
+	notok(t, err)
+
+	assert(t, dependency.MultiArchSame.String() == "same")
+	assert(t, dependency.MultiArchAllowed.String() == "allowed")
+}
+
+func TestMultiArchProperties(t *testing.T) {
+	assert(t, dependency.MultiArchSame.AllowsCoinstallation())
+	assert(t, !dependency.MultiArchForeign.AllowsCoinstallation())
+	assert(t, !dependency.MultiArchNo.AllowsCoinstallation())
+
+	assert(t, dependency.MultiArchForeign.IsSatisfiedByForeign())
+	assert(t, dependency.MultiArchAllowed.IsSatisfiedByForeign())
+	assert(t, !dependency.MultiArchSame.IsSatisfiedByForeign())
+	assert(t, !dependency.MultiArchNo.IsSatisfiedByForeign())
+}
+
+// vim: foldmethod=marker
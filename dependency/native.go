@@ -0,0 +1,74 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package dependency // import "github.com/akozlenkov/go-debian/dependency"
+
+import (
+	"runtime"
+)
+
+// Mapping of Go's runtime.GOOS values to Debian kernel names, where the
+// two differ.
+var debianKernelNames = map[string]string{
+	"freebsd": "kfreebsd",
+}
+
+// Mapping of Go's runtime.GOARCH values to Debian CPU names, where the two
+// differ. The Go `arm` port is assumed to be hard-float.
+var debianCPUNames = map[string]string{
+	"386":      "i386",
+	"arm":      "armhf",
+	"mipsle":   "mipsel",
+	"mips64le": "mips64el",
+	"ppc64le":  "ppc64el",
+}
+
+// Return the Debian kernel name (such as `linux`, `kfreebsd` or `hurd`)
+// of the system this program is running on.
+func KernelName() string {
+	if name, ok := debianKernelNames[runtime.GOOS]; ok {
+		return name
+	}
+	return runtime.GOOS
+}
+
+// Return the Debian CPU name (such as `amd64`, `i386` or `mips64el`) of
+// the system this program is running on.
+func CPUName() string {
+	if name, ok := debianCPUNames[runtime.GOARCH]; ok {
+		return name
+	}
+	return runtime.GOARCH
+}
+
+// Return the Debian Arch of the system this program is running on, built
+// out of KernelName and CPUName. This is exactly the Arch that ParseArch
+// returns for the Debian architecture name, so `amd64` for Linux on
+// x86-64, but `kfreebsd-amd64` for FreeBSD.
+func NativeArch() Arch {
+	name := CPUName()
+	if kernel := KernelName(); kernel != "linux" {
+		name = kernel + "-" + name
+	}
+	arch, _ := ParseArch(name)
+	return *arch
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,166 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package dependency // import "github.com/akozlenkov/go-debian/dependency"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidConflict is returned when a Build-Conflicts style relation
+// carries a version restriction that is not meaningful for a conflict.
+var ErrInvalidConflict = errors.New("invalid version restriction in conflict")
+
+// ErrVersionedEnhances is returned when an Enhances relation carries a
+// version restriction.
+var ErrVersionedEnhances = errors.New("version restriction in enhances")
+
+// Build-Conflicts {{{
+
+// Parse a Build-Conflicts (or Build-Conflicts-Indep) field. This uses the
+// same syntax as Build-Depends, but since these relations require the
+// absence of a package, strict version relations (`<<` and `>>`) are
+// rejected with an error wrapping ErrInvalidConflict.
+func ParseBuildConflicts(in string) (*Dependency, error) {
+	dep, err := Parse(in)
+	if err != nil {
+		return nil, err
+	}
+	for _, possibility := range dep.GetAllPossibilities() {
+		if !possibility.isConflictValid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidConflict, possibility)
+		}
+	}
+	return dep, nil
+}
+
+// Check that none of the Possibilities of this Dependency use a strict
+// version relation (`<<` or `>>`), which isn't meaningful in a conflict.
+func (dep *Dependency) IsConflictValid() bool {
+	for _, possibility := range dep.GetAllPossibilities() {
+		if !possibility.isConflictValid() {
+			return false
+		}
+	}
+	return true
+}
+
+func (possi Possibility) isConflictValid() bool {
+	if possi.Version == nil {
+		return true
+	}
+	switch possi.Version.Operator {
+	case "<<", ">>":
+		return false
+	}
+	return true
+}
+
+// }}}
+
+// Pre-Depends {{{
+
+// Packages which are commonly (and reasonably) the target of a Pre-Depends
+// relation, either because they're essential, or because they're needed by
+// maintainer scripts before a package is unpacked.
+var preDependsPackages = map[string]bool{
+	"adduser":             true,
+	"awk":                 true,
+	"base-files":          true,
+	"bash":                true,
+	"coreutils":           true,
+	"dash":                true,
+	"debconf":             true,
+	"debianutils":         true,
+	"diffutils":           true,
+	"dpkg":                true,
+	"findutils":           true,
+	"gawk":                true,
+	"grep":                true,
+	"gzip":                true,
+	"init-system-helpers": true,
+	"login":               true,
+	"mawk":                true,
+	"multiarch-support":   true,
+	"perl-base":           true,
+	"sed":                 true,
+	"sysvinit-utils":      true,
+	"tar":                 true,
+	"usrmerge":            true,
+	"util-linux":          true,
+}
+
+// Check to see if the named package looks like something that may sensibly
+// be pre-depended on. Any shared library is allowed, since essential
+// packages pre-depend on the libraries they link against.
+func isPreDependsPackage(name string) bool {
+	return preDependsPackages[name] || strings.HasPrefix(name, "lib")
+}
+
+// Parse a Pre-Depends field. This uses the same syntax as Depends, but
+// Debian Policy only allows Pre-Depends on essential system facilities.
+//
+// The names of any packages which don't look like such a thing (going by a
+// built-in list of common Pre-Depends targets, along with any shared
+// library) are returned as policy warnings, for a policy checker to
+// surface. These aren't a failure to parse, so the error is only set if
+// the field couldn't be parsed at all. Substvars are not checked.
+func ParsePreDepends(in string) (*Dependency, []string, error) {
+	dep, err := Parse(in)
+	if err != nil {
+		return nil, nil, err
+	}
+	suspect := []string{}
+	for _, possibility := range dep.GetAllPossibilities() {
+		if possibility.Substvar || isPreDependsPackage(possibility.Name) {
+			continue
+		}
+		suspect = append(suspect, possibility.Name)
+	}
+	return dep, suspect, nil
+}
+
+// }}}
+
+// Enhances {{{
+
+// Parse an Enhances field. Enhances is the reverse of Suggests; if package
+// A enhances package B, A improves the functionality of B when installed
+// alongside it. This uses the same syntax as Depends, but version
+// restrictions are not permitted, and are rejected with an error wrapping
+// ErrVersionedEnhances.
+func ParseEnhances(in string) (*Dependency, error) {
+	dep, err := Parse(in)
+	if err != nil {
+		return nil, err
+	}
+	for _, possibility := range dep.GetAllPossibilities() {
+		if possibility.Version != nil {
+			return nil, fmt.Errorf("%w: %s", ErrVersionedEnhances, possibility)
+		}
+	}
+	return dep, nil
+}
+
+// }}}
+
+// vim: foldmethod=marker
@@ -0,0 +1,86 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package dependency_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/dependency"
+)
+
+/*
+ *
+ */
+
+func TestParseBuildConflicts(t *testing.T) {
+	dep, err := dependency.ParseBuildConflicts("foo, bar (>= 1.0) | baz (= 2.0)")
+	isok(t, err)
+	assert(t, len(dep.Relations) == 2)
+	assert(t, dep.IsConflictValid())
+
+	_, err = dependency.ParseBuildConflicts("foo, bar (<< 1.0)")
+	notok(t, err)
+	assert(t, errors.Is(err, dependency.ErrInvalidConflict))
+
+	_, err = dependency.ParseBuildConflicts("foo (>> 1.0)")
+	assert(t, errors.Is(err, dependency.ErrInvalidConflict))
+}
+
+func TestIsConflictValid(t *testing.T) {
+	dep, err := dependency.Parse("foo (>> 1.0)")
+	isok(t, err)
+	assert(t, !dep.IsConflictValid())
+
+	dep, err = dependency.Parse("foo (<= 1.0) [amd64]")
+	isok(t, err)
+	assert(t, dep.IsConflictValid())
+}
+
+func TestParsePreDepends(t *testing.T) {
+	dep, warnings, err := dependency.ParsePreDepends("${misc:Pre-Depends}, dpkg (>= 1.17.14), libc6 (>= 2.34) | libc6.1")
+	isok(t, err)
+	assert(t, len(warnings) == 0)
+	assert(t, len(dep.Relations) == 3)
+
+	dep, warnings, err = dependency.ParsePreDepends("dpkg, apache2 | nginx")
+	isok(t, err)
+	assert(t, len(warnings) == 2)
+	assert(t, warnings[0] == "apache2" && warnings[1] == "nginx")
+	assert(t, len(dep.Relations) == 2)
+
+	_, warnings, err = dependency.ParsePreDepends("dpkg (>= ")
+	notok(t, err)
+	assert(t, warnings == nil)
+}
+
+func TestParseEnhances(t *testing.T) {
+	dep, err := dependency.ParseEnhances("emacs | xemacs21, vim [amd64]")
+	isok(t, err)
+	assert(t, len(dep.Relations) == 2)
+	assert(t, dep.Relations[0].Possibilities[1].Name == "xemacs21")
+
+	_, err = dependency.ParseEnhances("emacs (>= 1:27)")
+	notok(t, err)
+	assert(t, errors.Is(err, dependency.ErrVersionedEnhances))
+}
+
+// vim: foldmethod=marker
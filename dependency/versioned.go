@@ -0,0 +1,84 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package dependency // import "github.com/akozlenkov/go-debian/dependency"
+
+import (
+	"fmt"
+
+	"github.com/akozlenkov/go-debian/version"
+)
+
+// A VersionedPackage is a single package pinned to an exact version, such
+// as the entries of the Installed-Build-Depends field of a .buildinfo file:
+//
+//	Installed-Build-Depends: autoconf (= 2.71-3), libc6:amd64 (= 2.36-9)
+type VersionedPackage struct {
+	Name    string
+	Arch    *Arch
+	Version version.Version
+}
+
+// Parse a single `name[:arch] (= version)` entry into a VersionedPackage.
+// Entries which are anything other than a plain package with an exact
+// version relation are rejected.
+func ParseVersionedPackage(in string) (*VersionedPackage, error) {
+	dep, err := Parse(in)
+	if err != nil {
+		return nil, err
+	}
+	if len(dep.Relations) != 1 || len(dep.Relations[0].Possibilities) != 1 {
+		return nil, fmt.Errorf("Expected a single package, got '%s'", in)
+	}
+
+	possi := dep.Relations[0].Possibilities[0]
+	if possi.Substvar || possi.Version == nil || possi.Version.Operator != "=" {
+		return nil, fmt.Errorf("Expected an exact version for '%s'", in)
+	}
+
+	ver, err := version.Parse(possi.Version.Number)
+	if err != nil {
+		return nil, err
+	}
+	return &VersionedPackage{Name: possi.Name, Arch: possi.Arch, Version: ver}, nil
+}
+
+func (pkg *VersionedPackage) UnmarshalControl(data string) error {
+	parsed, err := ParseVersionedPackage(data)
+	if err != nil {
+		return err
+	}
+	*pkg = *parsed
+	return nil
+}
+
+func (pkg VersionedPackage) MarshalControl() (string, error) {
+	return pkg.String(), nil
+}
+
+func (pkg VersionedPackage) String() string {
+	name := pkg.Name
+	if pkg.Arch != nil {
+		name += ":" + pkg.Arch.String()
+	}
+	return fmt.Sprintf("%s (= %s)", name, pkg.Version)
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,59 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package dependency_test
+
+import (
+	"testing"
+
+	"github.com/akozlenkov/go-debian/dependency"
+)
+
+/*
+ *
+ */
+
+func TestParseVersionedPackage(t *testing.T) {
+	pkg, err := dependency.ParseVersionedPackage("libc6:amd64 (= 2.36-9)")
+	isok(t, err)
+	assert(t, pkg.Name == "libc6")
+	assert(t, pkg.Arch.CPU == "amd64")
+	assert(t, pkg.Version.Version == "2.36")
+	assert(t, pkg.Version.Revision == "9")
+	assert(t, pkg.String() == "libc6:amd64 (= 2.36-9)")
+
+	pkg, err = dependency.ParseVersionedPackage("autoconf (= 2.71-3)")
+	isok(t, err)
+	assert(t, pkg.Arch == nil)
+	assert(t, pkg.String() == "autoconf (= 2.71-3)")
+
+	for _, in := range []string{
+		"autoconf",
+		"autoconf (>= 2.71-3)",
+		"autoconf (= 2.71-3) | automake (= 1:1.16.5-1.3)",
+		"autoconf (= 2.71-3), automake (= 1:1.16.5-1.3)",
+		"${misc:Depends}",
+	} {
+		_, err := dependency.ParseVersionedPackage(in)
+		notok(t, err)
+	}
+}
+
+// vim: foldmethod=marker
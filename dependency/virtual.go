@@ -0,0 +1,111 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package dependency // import "github.com/akozlenkov/go-debian/dependency"
+
+// The virtual package names defined by Debian Policy, as listed in the
+// authoritative list of virtual package names.
+var knownVirtualPackages = map[string]bool{
+	"awk":                      true,
+	"c-compiler":               true,
+	"c-shell":                  true,
+	"cron-daemon":              true,
+	"dbus-session-bus":         true,
+	"dbus-system-bus":          true,
+	"debconf-2.0":              true,
+	"default-dbus-session-bus": true,
+	"default-dbus-system-bus":  true,
+	"default-logind":           true,
+	"dotfile-module":           true,
+	"editor":                   true,
+	"emacsen":                  true,
+	"fortran77-compiler":       true,
+	"httpd":                    true,
+	"httpd-cgi":                true,
+	"httpd-wsgi":               true,
+	"ident-server":             true,
+	"imap-client":              true,
+	"imap-server":              true,
+	"inet-superserver":         true,
+	"info-browser":             true,
+	"java-compiler":            true,
+	"java-virtual-machine":     true,
+	"kernel-headers":           true,
+	"kernel-image":             true,
+	"kernel-source":            true,
+	"linux-kernel-log-daemon":  true,
+	"logind":                   true,
+	"lzh-archiver":             true,
+	"mail-reader":              true,
+	"mail-transport-agent":     true,
+	"mailx":                    true,
+	"man-browser":              true,
+	"mpd-client":               true,
+	"news-reader":              true,
+	"news-transport-system":    true,
+	"pager":                    true,
+	"pgp":                      true,
+	"pop3-server":              true,
+	"postscript-preview":       true,
+	"postscript-viewer":        true,
+	"radius-server":            true,
+	"rsh-client":               true,
+	"rsh-server":               true,
+	"scheme-ieee-11878-1900":   true,
+	"scheme-r4rs":              true,
+	"scheme-r5rs":              true,
+	"stardict":                 true,
+	"stardict-dictdata":        true,
+	"system-log-daemon":        true,
+	"tclsh":                    true,
+	"telnet-client":            true,
+	"telnet-server":            true,
+	"time-daemon":              true,
+	"ups-monitor":              true,
+	"wish":                     true,
+	"www-browser":              true,
+	"x-audio-mixer":            true,
+	"x-display-manager":        true,
+	"x-session-manager":        true,
+	"x-terminal-emulator":      true,
+	"x-window-manager":         true,
+	"xserver":                  true,
+}
+
+// Check to see if this Possibility names one of the virtual packages
+// defined by Debian Policy, such as `awk`, `editor` or `www-browser`.
+// Without a package database, there's no way of knowing about every
+// virtual package, so a false return doesn't mean the package is real.
+func (possi Possibility) CouldBeVirtual() bool {
+	return knownVirtualPackages[possi.Name]
+}
+
+// Check to see if any Possibility of this Dependency could be a virtual
+// package, as Possibility.CouldBeVirtual.
+func (dep *Dependency) CouldBeVirtual() bool {
+	for _, possibility := range dep.GetAllPossibilities() {
+		if possibility.CouldBeVirtual() {
+			return true
+		}
+	}
+	return false
+}
+
+// vim: foldmethod=marker
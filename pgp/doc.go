@@ -0,0 +1,7 @@
+/*
+Work with the OpenPGP keys used to sign Debian (and Debian derived)
+archives, such as exporting them for use with APT's `signed-by` option,
+and verify the chain of trust from a signed InRelease down to the .deb
+files of an archive.
+*/
+package pgp // import "github.com/akozlenkov/go-debian/pgp"
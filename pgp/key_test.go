@@ -0,0 +1,56 @@
+package pgp_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/pgp"
+	"golang.org/x/crypto/openpgp"
+)
+
+/*
+ *
+ */
+
+func newTestEntity(t *testing.T) *openpgp.Entity {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Test Archive Key", "testing only", "archive@example.com", nil)
+	isok(t, err)
+	return entity
+}
+
+func TestBinaryPublicKey(t *testing.T) {
+	entity := newTestEntity(t)
+
+	key, err := pgp.BinaryPublicKey(entity)
+	isok(t, err)
+	keyring, err := openpgp.ReadKeyRing(bytes.NewReader(key))
+	isok(t, err)
+	assert(t, len(keyring) == 1)
+	assert(t, keyring[0].PrivateKey == nil)
+	assert(t, keyring[0].PrimaryKey.KeyId == entity.PrimaryKey.KeyId)
+}
+
+func TestArmoredPublicKey(t *testing.T) {
+	entity := newTestEntity(t)
+
+	key, err := pgp.ArmoredPublicKey(entity)
+	isok(t, err)
+	assert(t, strings.HasPrefix(string(key), "-----BEGIN PGP PUBLIC KEY BLOCK-----"))
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(key))
+	isok(t, err)
+	assert(t, len(keyring) == 1)
+	assert(t, keyring[0].PrimaryKey.KeyId == entity.PrimaryKey.KeyId)
+}
+
+func TestKeyFingerprint(t *testing.T) {
+	entity := newTestEntity(t)
+
+	fingerprint := pgp.KeyFingerprint(entity)
+	assert(t, len(fingerprint) == 40)
+	assert(t, fingerprint == strings.ToUpper(fingerprint))
+	assert(t, strings.HasSuffix(fingerprint, entity.PrimaryKey.KeyIdString()))
+}
+
+// vim: foldmethod=marker
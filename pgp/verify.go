@@ -0,0 +1,263 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package pgp // import "github.com/akozlenkov/go-debian/pgp"
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/dependency"
+	"github.com/akozlenkov/go-debian/repository"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+// A VerificationStep identifies a link in the chain of trust from the
+// signed InRelease file of a suite down to the .deb files it references.
+type VerificationStep string
+
+const (
+	// The OpenPGP signature of the InRelease file.
+	StepInRelease VerificationStep = "InRelease"
+	// The checksum of the Packages.gz index, as listed in the InRelease.
+	StepPackages VerificationStep = "Packages"
+	// The checksum of a .deb, as listed in the Packages index.
+	StepDeb VerificationStep = "deb"
+)
+
+// VerificationChainError is returned by VerifyRepository, and identifies
+// the Step at which the chain of trust broke, and the URL of the file which
+// failed to fetch or verify.
+type VerificationChainError struct {
+	Step VerificationStep
+	URL  string
+	Err  error
+}
+
+func (e *VerificationChainError) Error() string {
+	return fmt.Sprintf("%s verification failed for '%s': %s", e.Step, e.URL, e.Err)
+}
+
+func (e *VerificationChainError) Unwrap() error {
+	return e.Err
+}
+
+// Verify the complete chain of trust of the given component and
+// architecture of a suite of the archive at baseURL, in the same way
+// APT does:
+//
+//   - the InRelease file must be signed by a key in keyring
+//   - the InRelease must not be past its Valid-Until date, if it has one
+//   - the Suite or Codename of the InRelease must match suite
+//   - the Packages.gz index must match its checksum in the InRelease
+//   - every .deb listed in the Packages index must match its checksum there
+//
+// Every .deb in the index is fetched, so this is only practical for small
+// archives. If anything fails to fetch or verify, a *VerificationChainError
+// is returned.
+func VerifyRepository(
+	ctx context.Context,
+	fetcher repository.Fetcher,
+	baseURL, suite, component string,
+	arch dependency.Arch,
+	keyring openpgp.KeyRing,
+) error {
+	baseURL = strings.TrimRight(baseURL, "/")
+	distsURL := fmt.Sprintf("%s/dists/%s", baseURL, suite)
+
+	inReleaseURL := distsURL + "/InRelease"
+	release, err := verifyInRelease(ctx, fetcher, inReleaseURL, keyring)
+	if err == nil {
+		err = checkRelease(release, suite, time.Now())
+	}
+	if err != nil {
+		return &VerificationChainError{Step: StepInRelease, URL: inReleaseURL, Err: err}
+	}
+
+	packagesPath := fmt.Sprintf("%s/binary-%s/Packages.gz", component, arch.String())
+	packagesURL := distsURL + "/" + packagesPath
+	packages, err := verifyPackages(ctx, fetcher, packagesURL, release, packagesPath)
+	if err != nil {
+		return &VerificationChainError{Step: StepPackages, URL: packagesURL, Err: err}
+	}
+
+	for _, pkg := range packages {
+		debURL := baseURL + "/" + strings.TrimPrefix(pkg.Filename, "/")
+		if err := verifyDeb(ctx, fetcher, debURL, pkg); err != nil {
+			return &VerificationChainError{Step: StepDeb, URL: debURL, Err: err}
+		}
+	}
+	return nil
+}
+
+// The largest InRelease file VerifyRepository will read, since its size
+// isn't known ahead of time.
+const maxInReleaseSize = 16 << 20
+
+// Fetch the given URL into memory, failing if it's more than limit bytes,
+// so a hostile mirror can't send an endless body.
+func fetchLimited(ctx context.Context, fetcher repository.Fetcher, url string, limit int64) ([]byte, error) {
+	reader, err := fetcher.Fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("Response larger than %d bytes", limit)
+	}
+	return data, nil
+}
+
+// Check the size and sha256 of what reader returns against the expected
+// values, reading no more than one byte past the expected size.
+func checkSHA256(reader io.Reader, hash string, size int64) error {
+	hasher := sha256.New()
+	n, err := io.Copy(hasher, io.LimitReader(reader, size+1))
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("Size mismatch: expected %d, got %d", size, n)
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(actual, hash) {
+		return fmt.Errorf("SHA256 mismatch: expected '%s', got '%s'", hash, actual)
+	}
+	return nil
+}
+
+func verifyInRelease(
+	ctx context.Context,
+	fetcher repository.Fetcher,
+	url string,
+	keyring openpgp.KeyRing,
+) (*repository.Release, error) {
+	data, err := fetchLimited(ctx, fetcher, url, maxInReleaseSize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := clearsign.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("InRelease is not clearsigned")
+	}
+	if _, err := openpgp.CheckDetachedSignature(
+		keyring,
+		bytes.NewReader(block.Bytes),
+		block.ArmoredSignature.Body,
+	); err != nil {
+		return nil, err
+	}
+
+	return repository.ParseRelease(bufio.NewReader(bytes.NewReader(block.Plaintext)))
+}
+
+// The layouts used for the Date and Valid-Until fields of a Release.
+var releaseDateLayouts = []string{time.RFC1123, time.RFC1123Z}
+
+// Check that the Release is for the given suite, and hasn't expired as of
+// now, so that an old (or mismatched) InRelease replayed by a mirror isn't
+// trusted just because its signature is good.
+func checkRelease(release *repository.Release, suite string, now time.Time) error {
+	if release.Suite != suite && release.Codename != suite {
+		return fmt.Errorf(
+			"Release is for suite '%s' (codename '%s'), not '%s'",
+			release.Suite, release.Codename, suite,
+		)
+	}
+
+	if release.ValidUntil == "" {
+		return nil
+	}
+	for _, layout := range releaseDateLayouts {
+		validUntil, err := time.Parse(layout, release.ValidUntil)
+		if err != nil {
+			continue
+		}
+		if now.After(validUntil) {
+			return fmt.Errorf("Release expired at '%s'", release.ValidUntil)
+		}
+		return nil
+	}
+	return fmt.Errorf("Unparsable Valid-Until '%s'", release.ValidUntil)
+}
+
+func verifyPackages(
+	ctx context.Context,
+	fetcher repository.Fetcher,
+	url string,
+	release *repository.Release,
+	relativePath string,
+) ([]control.BinaryIndex, error) {
+	hash, size, err := release.HashForFile(relativePath, "sha256")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := fetchLimited(ctx, fetcher, url, size)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSHA256(bytes.NewReader(data), hash, size); err != nil {
+		return nil, err
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return control.ParseBinaryIndex(bufio.NewReader(reader))
+}
+
+func verifyDeb(
+	ctx context.Context,
+	fetcher repository.Fetcher,
+	url string,
+	pkg control.BinaryIndex,
+) error {
+	if pkg.SHA256 == "" {
+		return fmt.Errorf("Package '%s' has no SHA256 in the Packages index", pkg.Package)
+	}
+
+	reader, err := fetcher.Fetch(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return checkSHA256(reader, pkg.SHA256, int64(pkg.Size))
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,218 @@
+package pgp_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/akozlenkov/go-debian/dependency"
+	"github.com/akozlenkov/go-debian/pgp"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+/*
+ *
+ */
+
+type mapFetcher map[string][]byte
+
+func (m mapFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	data, ok := m[url]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Build a tiny signed archive at http://archive.example.com with one .deb
+// in main/binary-amd64.
+func newTestArchive(t *testing.T, signer *openpgp.Entity) mapFetcher {
+	t.Helper()
+	return newTestArchiveWithRelease(t, signer, "Suite: unstable\n")
+}
+
+// Build the archive of newTestArchive, with the given fields at the top of
+// its Release.
+func newTestArchiveWithRelease(t *testing.T, signer *openpgp.Entity, header string) mapFetcher {
+	t.Helper()
+	const base = "http://archive.example.com"
+
+	deb := []byte("not really a .deb")
+	debSum := sha256.Sum256(deb)
+	packages := fmt.Sprintf(`Package: hello
+Version: 1.0-1
+Architecture: amd64
+Filename: pool/main/h/hello/hello_1.0-1_amd64.deb
+Size: %d
+SHA256: %x
+`, len(deb), debSum)
+
+	packagesGz := bytes.Buffer{}
+	writer := gzip.NewWriter(&packagesGz)
+	_, err := writer.Write([]byte(packages))
+	isok(t, err)
+	isok(t, writer.Close())
+	packagesSum := sha256.Sum256(packagesGz.Bytes())
+
+	release := header + fmt.Sprintf(`Architectures: amd64
+Components: main
+SHA256:
+ %x %d main/binary-amd64/Packages.gz
+`, packagesSum, packagesGz.Len())
+
+	inRelease := bytes.Buffer{}
+	plaintext, err := clearsign.Encode(&inRelease, signer.PrivateKey, nil)
+	isok(t, err)
+	_, err = plaintext.Write([]byte(release))
+	isok(t, err)
+	isok(t, plaintext.Close())
+
+	return mapFetcher{
+		base + "/dists/unstable/InRelease":                     inRelease.Bytes(),
+		base + "/dists/unstable/main/binary-amd64/Packages.gz": packagesGz.Bytes(),
+		base + "/pool/main/h/hello/hello_1.0-1_amd64.deb":      deb,
+	}
+}
+
+func verifyTestArchive(fetcher mapFetcher, keyring openpgp.KeyRing) error {
+	return pgp.VerifyRepository(
+		context.Background(),
+		fetcher,
+		"http://archive.example.com/",
+		"unstable",
+		"main",
+		dependency.Arch{CPU: "amd64", OS: "linux", ABI: "gnu"},
+		keyring,
+	)
+}
+
+func chainStep(t *testing.T, err error) pgp.VerificationStep {
+	t.Helper()
+	chainErr := &pgp.VerificationChainError{}
+	assert(t, errors.As(err, &chainErr))
+	return chainErr.Step
+}
+
+func TestVerifyRepository(t *testing.T) {
+	signer := newTestEntity(t)
+	keyring := openpgp.EntityList{signer}
+
+	isok(t, verifyTestArchive(newTestArchive(t, signer), keyring))
+}
+
+func TestVerifyRepositoryBadSignature(t *testing.T) {
+	signer := newTestEntity(t)
+	other := newTestEntity(t)
+
+	err := verifyTestArchive(newTestArchive(t, signer), openpgp.EntityList{other})
+	notok(t, err)
+	assert(t, chainStep(t, err) == pgp.StepInRelease)
+}
+
+func TestVerifyRepositoryBadPackages(t *testing.T) {
+	signer := newTestEntity(t)
+	fetcher := newTestArchive(t, signer)
+	packages := "http://archive.example.com/dists/unstable/main/binary-amd64/Packages.gz"
+	fetcher[packages] = append([]byte{}, fetcher[packages]...)
+	fetcher[packages][len(fetcher[packages])-1] ^= 0xff
+
+	err := verifyTestArchive(fetcher, openpgp.EntityList{signer})
+	notok(t, err)
+	assert(t, chainStep(t, err) == pgp.StepPackages)
+}
+
+func TestVerifyRepositoryBadDeb(t *testing.T) {
+	signer := newTestEntity(t)
+	fetcher := newTestArchive(t, signer)
+	deb := "http://archive.example.com/pool/main/h/hello/hello_1.0-1_amd64.deb"
+	fetcher[deb] = []byte("not really a .deb, tampered")
+
+	err := verifyTestArchive(fetcher, openpgp.EntityList{signer})
+	notok(t, err)
+	assert(t, chainStep(t, err) == pgp.StepDeb)
+
+	chainErr := &pgp.VerificationChainError{}
+	assert(t, errors.As(err, &chainErr))
+	assert(t, chainErr.URL == deb)
+}
+
+// An endless stream of zeros, from a hostile mirror.
+type endlessReader struct{}
+
+func (endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+type endlessFetcher struct {
+	mapFetcher
+	endless string
+}
+
+func (f endlessFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	if url == f.endless {
+		return io.NopCloser(endlessReader{}), nil
+	}
+	return f.mapFetcher.Fetch(ctx, url)
+}
+
+func TestVerifyRepositoryEndlessBody(t *testing.T) {
+	signer := newTestEntity(t)
+	keyring := openpgp.EntityList{signer}
+
+	for step, url := range map[pgp.VerificationStep]string{
+		pgp.StepInRelease: "http://archive.example.com/dists/unstable/InRelease",
+		pgp.StepPackages:  "http://archive.example.com/dists/unstable/main/binary-amd64/Packages.gz",
+		pgp.StepDeb:       "http://archive.example.com/pool/main/h/hello/hello_1.0-1_amd64.deb",
+	} {
+		fetcher := endlessFetcher{mapFetcher: newTestArchive(t, signer), endless: url}
+		err := pgp.VerifyRepository(
+			context.Background(),
+			fetcher,
+			"http://archive.example.com/",
+			"unstable",
+			"main",
+			dependency.Arch{CPU: "amd64", OS: "linux", ABI: "gnu"},
+			keyring,
+		)
+		notok(t, err)
+		assert(t, chainStep(t, err) == step)
+	}
+}
+
+func TestVerifyRepositoryRelease(t *testing.T) {
+	signer := newTestEntity(t)
+	keyring := openpgp.EntityList{signer}
+	future := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC1123)
+	past := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC1123)
+
+	for _, header := range []string{
+		"Codename: unstable\n",
+		"Suite: unstable\nValid-Until: " + future + "\n",
+	} {
+		isok(t, verifyTestArchive(newTestArchiveWithRelease(t, signer, header), keyring))
+	}
+
+	for _, header := range []string{
+		"Suite: stable\nCodename: bookworm\n",
+		"",
+		"Suite: unstable\nValid-Until: " + past + "\n",
+		"Suite: unstable\nValid-Until: tomorrow\n",
+	} {
+		err := verifyTestArchive(newTestArchiveWithRelease(t, signer, header), keyring)
+		notok(t, err)
+		assert(t, chainStep(t, err) == pgp.StepInRelease)
+	}
+}
+
+// vim: foldmethod=marker
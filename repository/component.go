@@ -0,0 +1,53 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"strings"
+)
+
+// The archive areas (components) defined by Debian Policy.
+var knownComponents = map[string]bool{
+	"main":              true,
+	"contrib":           true,
+	"non-free":          true,
+	"non-free-firmware": true,
+}
+
+// Split a fully qualified section of a package, such as `non-free/video`,
+// into the component (`non-free`) and the section (`video`). Packages in
+// main don't have the component in their section, so if there's no
+// component, it's taken to be `main`.
+func ParseComponentSection(s string) (component, section string) {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndex(s, "/"); i != -1 {
+		return s[:i], s[i+1:]
+	}
+	return "main", s
+}
+
+// Check to see if the component is one of the Debian archive areas, that
+// is, `main`, `contrib`, `non-free` or `non-free-firmware`.
+func IsKnownComponent(s string) bool {
+	return knownComponents[s]
+}
+
+// vim: foldmethod=marker
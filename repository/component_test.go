@@ -0,0 +1,36 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/akozlenkov/go-debian/repository"
+)
+
+/*
+ *
+ */
+
+func TestParseComponentSection(t *testing.T) {
+	for in, expected := range map[string][2]string{
+		"non-free/video":           {"non-free", "video"},
+		"contrib/games":            {"contrib", "games"},
+		"non-free-firmware/kernel": {"non-free-firmware", "kernel"},
+		"utils":                    {"main", "utils"},
+		" devel ":                  {"main", "devel"},
+	} {
+		component, section := repository.ParseComponentSection(in)
+		assert(t, component == expected[0])
+		assert(t, section == expected[1])
+	}
+}
+
+func TestIsKnownComponent(t *testing.T) {
+	for _, component := range []string{"main", "contrib", "non-free", "non-free-firmware"} {
+		assert(t, repository.IsKnownComponent(component))
+	}
+	for _, component := range []string{"", "universe", "Main", "non-free/video"} {
+		assert(t, !repository.IsKnownComponent(component))
+	}
+}
+
+// vim: foldmethod=marker
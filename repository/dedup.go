@@ -0,0 +1,87 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"github.com/akozlenkov/go-debian/control"
+)
+
+// The identity of an entry in a Packages file. Two entries with the same
+// packageKey describe the same .deb, as far as APT is concerned.
+type packageKey struct {
+	Package      string
+	Version      string
+	Architecture string
+}
+
+func keyOf(pkg *control.Paragraph) packageKey {
+	return packageKey{
+		Package:      pkg.Values["Package"],
+		Version:      pkg.Values["Version"],
+		Architecture: pkg.Values["Architecture"],
+	}
+}
+
+// Remove duplicate entries (those with the same Package, Version and
+// Architecture) from a Packages index. As APT does, the last of the
+// duplicate entries is kept, and the others are dropped. The kept entries
+// are returned in the order they appear in pkgs.
+func DedupPackageIndex(pkgs []*control.Paragraph) []*control.Paragraph {
+	last := map[packageKey]int{}
+	for i, pkg := range pkgs {
+		last[keyOf(pkg)] = i
+	}
+
+	ret := []*control.Paragraph{}
+	for i, pkg := range pkgs {
+		if last[keyOf(pkg)] == i {
+			ret = append(ret, pkg)
+		}
+	}
+	return ret
+}
+
+// Return the groups of duplicate entries (those with the same Package,
+// Version and Architecture) in a Packages index, for reporting on broken
+// mirrors. Entries which only appear once are not returned. Groups are
+// returned in the order their first entry appears in pkgs, and the entries
+// of each group are in the order they appear in pkgs.
+func FindDuplicates(pkgs []*control.Paragraph) [][]*control.Paragraph {
+	groups := map[packageKey][]*control.Paragraph{}
+	order := []packageKey{}
+	for _, pkg := range pkgs {
+		key := keyOf(pkg)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], pkg)
+	}
+
+	ret := [][]*control.Paragraph{}
+	for _, key := range order {
+		if len(groups[key]) > 1 {
+			ret = append(ret, groups[key])
+		}
+	}
+	return ret
+}
+
+// vim: foldmethod=marker
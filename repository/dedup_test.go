@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/repository"
+)
+
+/*
+ *
+ */
+
+// Duplicated Packages file {{{
+const duplicatedPackages = `Package: hello
+Version: 2.10-3
+Architecture: amd64
+Filename: pool/main/h/hello/hello_2.10-3_amd64.deb
+
+Package: hello
+Version: 2.10-3
+Architecture: i386
+
+Package: fbautostart
+Version: 2.718281828-1
+Architecture: amd64
+
+Package: hello
+Version: 2.10-3
+Architecture: amd64
+Filename: pool/main/h/hello/hello_2.10-3_amd64.deb?mirror=2
+
+Package: hello
+Version: 2.10-2
+Architecture: amd64
+`
+
+// }}}
+
+func readDuplicatedPackages(t *testing.T) []*control.Paragraph {
+	reader, err := control.NewParagraphReader(strings.NewReader(duplicatedPackages), nil)
+	isok(t, err)
+	paras, err := reader.All()
+	isok(t, err)
+	ret := []*control.Paragraph{}
+	for i := range paras {
+		ret = append(ret, &paras[i])
+	}
+	return ret
+}
+
+func TestDedupPackageIndex(t *testing.T) {
+	pkgs := readDuplicatedPackages(t)
+	deduped := repository.DedupPackageIndex(pkgs)
+	assert(t, len(deduped) == 4)
+	assert(t, deduped[0].Values["Architecture"] == "i386")
+	assert(t, deduped[1].Values["Package"] == "fbautostart")
+	assert(t, strings.HasSuffix(deduped[2].Values["Filename"], "?mirror=2"))
+	assert(t, deduped[3].Values["Version"] == "2.10-2")
+
+	assert(t, len(repository.DedupPackageIndex(nil)) == 0)
+}
+
+func TestFindDuplicates(t *testing.T) {
+	pkgs := readDuplicatedPackages(t)
+	duplicates := repository.FindDuplicates(pkgs)
+	assert(t, len(duplicates) == 1)
+	assert(t, len(duplicates[0]) == 2)
+	assert(t, duplicates[0][0] == pkgs[0])
+	assert(t, duplicates[0][1] == pkgs[3])
+
+	assert(t, len(repository.FindDuplicates(pkgs[:3])) == 0)
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,5 @@
+/*
+Inspect and work with Debian (and Debian derived) archives, such as the
+Release files of a suite, and the Packages indices they list.
+*/
+package repository // import "github.com/akozlenkov/go-debian/repository"
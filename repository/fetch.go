@@ -0,0 +1,169 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// A Fetcher retrieves files (such as InRelease, Packages.gz or .deb files)
+// from an archive by URL. The caller is responsible for closing the
+// returned ReadCloser.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (io.ReadCloser, error)
+}
+
+// HTTPFetcher is a Fetcher which fetches files over HTTP(S) using the given
+// Client, or http.DefaultClient if Client is nil.
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+// Fetch the given URL, returning an error if the server doesn't respond
+// with `200 OK`.
+func (f HTTPFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	resp, err := f.get(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Failed to fetch '%s': %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// Send a GET request for the given URL, with any extra headers given.
+func (f HTTPFetcher) get(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	return client.Do(req)
+}
+
+// ConditionalFetcher {{{
+
+// ErrNotModified is returned by ConditionalFetcher when the file hasn't
+// changed since it was last fetched, so the caller's copy may be reused.
+var ErrNotModified = errors.New("not modified")
+
+// The validators sent back with a response, which are used to make the
+// next request for the same URL conditional.
+type cacheValidators struct {
+	ETag         string
+	LastModified string
+}
+
+// ConditionalFetcher is a Fetcher which remembers the `ETag` and
+// `Last-Modified` headers of each URL it fetches, and sends them back as
+// `If-None-Match` and `If-Modified-Since` the next time the URL is fetched.
+// If the server responds with `304 Not Modified`, Fetch returns
+// ErrNotModified, and the caller should use the copy it already has.
+//
+// This is safe for concurrent use, and the zero value is ready to use.
+type ConditionalFetcher struct {
+	HTTPFetcher
+
+	lock       sync.Mutex
+	validators map[string]cacheValidators
+}
+
+// Create a new ConditionalFetcher, sending its requests with the given
+// HTTPFetcher.
+func NewConditionalFetcher(fetcher HTTPFetcher) *ConditionalFetcher {
+	return &ConditionalFetcher{
+		HTTPFetcher: fetcher,
+		validators:  map[string]cacheValidators{},
+	}
+}
+
+// Fetch the given URL, unless it hasn't been modified since it was last
+// fetched, in which case ErrNotModified is returned.
+func (f *ConditionalFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	f.lock.Lock()
+	cached, ok := f.validators[url]
+	f.lock.Unlock()
+
+	header := http.Header{}
+	if ok && cached.ETag != "" {
+		header.Set("If-None-Match", cached.ETag)
+	}
+	if ok && cached.LastModified != "" {
+		header.Set("If-Modified-Since", cached.LastModified)
+	}
+
+	resp, err := f.get(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		validators := cacheValidators{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}
+		f.lock.Lock()
+		if validators.ETag != "" || validators.LastModified != "" {
+			if f.validators == nil {
+				f.validators = map[string]cacheValidators{}
+			}
+			f.validators[url] = validators
+		} else {
+			delete(f.validators, url)
+		}
+		f.lock.Unlock()
+		return resp.Body, nil
+	case http.StatusNotModified:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: '%s'", ErrNotModified, url)
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("Failed to fetch '%s': %s", url, resp.Status)
+	}
+}
+
+// Forget the validators of the given URL, so the next Fetch of it is
+// unconditional. This should be called if the caller's copy of the file
+// has been lost.
+func (f *ConditionalFetcher) Forget(url string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.validators, url)
+}
+
+// }}}
+
+// vim: foldmethod=marker
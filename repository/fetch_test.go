@@ -0,0 +1,120 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/repository"
+)
+
+/*
+ *
+ */
+
+func TestHTTPFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dists/unstable/InRelease" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("Suite: unstable\n"))
+	}))
+	defer server.Close()
+
+	fetcher := repository.HTTPFetcher{}
+	reader, err := fetcher.Fetch(context.Background(), server.URL+"/dists/unstable/InRelease")
+	isok(t, err)
+	data, err := io.ReadAll(reader)
+	isok(t, err)
+	isok(t, reader.Close())
+	assert(t, string(data) == "Suite: unstable\n")
+
+	_, err = fetcher.Fetch(context.Background(), server.URL+"/dists/unstable/Release")
+	notok(t, err)
+}
+
+func TestConditionalFetcher(t *testing.T) {
+	requests := []*http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		switch r.URL.Path {
+		case "/etag":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+		case "/last-modified":
+			if r.Header.Get("If-Modified-Since") == "Sat, 10 Aug 2024 20:04:42 GMT" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", "Sat, 10 Aug 2024 20:04:42 GMT")
+		}
+		w.Write([]byte("Suite: unstable\n"))
+	}))
+	defer server.Close()
+
+	fetcher := repository.NewConditionalFetcher(repository.HTTPFetcher{})
+	ctx := context.Background()
+
+	for _, path := range []string{"/etag", "/last-modified"} {
+		reader, err := fetcher.Fetch(ctx, server.URL+path)
+		isok(t, err)
+		data, err := io.ReadAll(reader)
+		isok(t, err)
+		reader.Close()
+		assert(t, string(data) == "Suite: unstable\n")
+
+		_, err = fetcher.Fetch(ctx, server.URL+path)
+		assert(t, errors.Is(err, repository.ErrNotModified))
+	}
+
+	fetcher.Forget(server.URL + "/etag")
+	reader, err := fetcher.Fetch(ctx, server.URL+"/etag")
+	isok(t, err)
+	reader.Close()
+	assert(t, requests[len(requests)-1].Header.Get("If-None-Match") == "")
+
+	// Without any validators, every fetch is unconditional.
+	for i := 0; i < 2; i++ {
+		reader, err := fetcher.Fetch(ctx, server.URL+"/plain")
+		isok(t, err)
+		reader.Close()
+	}
+	last := requests[len(requests)-1]
+	assert(t, last.Header.Get("If-None-Match") == "" && last.Header.Get("If-Modified-Since") == "")
+}
+
+func TestConditionalFetcherZeroValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("Suite: unstable\n"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	for _, fetcher := range []*repository.ConditionalFetcher{
+		{},
+		{HTTPFetcher: repository.HTTPFetcher{Client: server.Client()}},
+	} {
+		fetcher.Forget(server.URL)
+
+		reader, err := fetcher.Fetch(ctx, server.URL)
+		isok(t, err)
+		reader.Close()
+
+		_, err = fetcher.Fetch(ctx, server.URL)
+		assert(t, errors.Is(err, repository.ErrNotModified))
+	}
+}
+
+// vim: foldmethod=marker
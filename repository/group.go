@@ -0,0 +1,41 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"github.com/akozlenkov/go-debian/control"
+)
+
+// Group the entries of a Packages index by the name of the source package
+// they were built from, as given by BinaryIndex.SourcePackage, which is
+// the Source field (without any binNMU version), or the Package when there
+// is no Source field. The entries of each group are in the order they
+// appear in pkgs.
+func GroupBySource(pkgs []*control.BinaryIndex) map[string][]*control.BinaryIndex {
+	ret := map[string][]*control.BinaryIndex{}
+	for _, pkg := range pkgs {
+		source := pkg.SourcePackage()
+		ret[source] = append(ret[source], pkg)
+	}
+	return ret
+}
+
+// vim: foldmethod=marker
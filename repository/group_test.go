@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/repository"
+)
+
+/*
+ *
+ */
+
+func TestGroupBySource(t *testing.T) {
+	// Packages {{{
+	index, err := control.ParseBinaryIndex(bufio.NewReader(strings.NewReader(`Package: hello
+Version: 2.10-3
+
+Package: libfoo1
+Source: foo
+Version: 1.0-1
+
+Package: libfoo-dev
+Source: foo (1.0-1)
+Version: 1.0-1+b1
+
+Package: foo-doc
+Source: foo
+Version: 1.0-1
+`)))
+	// }}}
+	isok(t, err)
+	pkgs := []*control.BinaryIndex{}
+	for i := range index {
+		pkgs = append(pkgs, &index[i])
+	}
+
+	groups := repository.GroupBySource(pkgs)
+	assert(t, len(groups) == 2)
+	assert(t, len(groups["hello"]) == 1)
+	assert(t, groups["hello"][0] == pkgs[0])
+	assert(t, len(groups["foo"]) == 3)
+	assert(t, groups["foo"][0].Package == "libfoo1")
+	assert(t, groups["foo"][1].Package == "libfoo-dev")
+	assert(t, groups["foo"][2].Package == "foo-doc")
+
+	assert(t, len(repository.GroupBySource(nil)) == 0)
+}
+
+// vim: foldmethod=marker
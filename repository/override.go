@@ -0,0 +1,114 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+// An OverrideEntry is a single line of an archive override file, such as
+// `indices/override.bookworm.main`, which sets the Priority and Section
+// (and, rarely, the Maintainer) of a package in the archive, regardless of
+// what the package itself claims.
+type OverrideEntry struct {
+	Package  string
+	Priority string
+	Section  string
+
+	// The Maintainer override is optional, and empty if not set.
+	Maintainer string
+}
+
+// Parse the override file in the given reader. Each line consists of the
+// package name, priority and section, optionally followed by a maintainer
+// override, which may be in the `old => new` form. Blank lines and lines
+// starting with `#` are ignored.
+func ParseOverride(r io.Reader) ([]*OverrideEntry, error) {
+	ret := []*OverrideEntry{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("Bad override line: '%s'", line)
+		}
+
+		maintainer := strings.Join(fields[3:], " ")
+		if i := strings.Index(maintainer, "=>"); i != -1 {
+			maintainer = strings.TrimSpace(maintainer[i+2:])
+		}
+
+		ret = append(ret, &OverrideEntry{
+			Package:    fields[0],
+			Priority:   fields[1],
+			Section:    fields[2],
+			Maintainer: maintainer,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Patch the given packages with the values from the matching override
+// entries. Both the struct members and the underlying Paragraph (if the
+// package was decoded from a control file) are updated. The same slice of
+// packages is returned, to allow chaining.
+func ApplyOverrides(pkgs []*control.BinaryIndex, overrides []*OverrideEntry) []*control.BinaryIndex {
+	byName := map[string]*OverrideEntry{}
+	for _, override := range overrides {
+		byName[override.Package] = override
+	}
+
+	for _, pkg := range pkgs {
+		override, ok := byName[pkg.Package]
+		if !ok {
+			continue
+		}
+		pkg.Priority = override.Priority
+		pkg.Section = override.Section
+		if override.Maintainer != "" {
+			pkg.Maintainer = override.Maintainer
+		}
+
+		if pkg.Values == nil {
+			continue
+		}
+		pkg.Set("Priority", pkg.Priority)
+		pkg.Set("Section", pkg.Section)
+		if override.Maintainer != "" {
+			pkg.Set("Maintainer", pkg.Maintainer)
+		}
+	}
+	return pkgs
+}
+
+// vim: foldmethod=marker
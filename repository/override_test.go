@@ -0,0 +1,69 @@
+package repository_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/repository"
+)
+
+/*
+ *
+ */
+
+// Override file {{{
+const overrideFile = `# override.sid.main
+hello		important	utils
+libhello-dev	optional	libdevel
+
+fbautostart	optional	x11	Paul Tagliamonte <paultag@ubuntu.com> => Paul Tagliamonte <paultag@debian.org>
+`
+
+// }}}
+
+func TestParseOverride(t *testing.T) {
+	overrides, err := repository.ParseOverride(strings.NewReader(overrideFile))
+	isok(t, err)
+	assert(t, len(overrides) == 3)
+	assert(t, *overrides[0] == repository.OverrideEntry{
+		Package:  "hello",
+		Priority: "important",
+		Section:  "utils",
+	})
+	assert(t, overrides[2].Maintainer == "Paul Tagliamonte <paultag@debian.org>")
+
+	_, err = repository.ParseOverride(strings.NewReader("hello optional\n"))
+	notok(t, err)
+}
+
+func TestApplyOverrides(t *testing.T) {
+	overrides, err := repository.ParseOverride(strings.NewReader(overrideFile))
+	isok(t, err)
+
+	decoder, err := control.NewDecoder(strings.NewReader(packagesIndex), nil)
+	isok(t, err)
+	pkgs := []*control.BinaryIndex{}
+	for i := 0; i < 4; i++ {
+		pkg := control.BinaryIndex{}
+		isok(t, decoder.Decode(&pkg))
+		pkgs = append(pkgs, &pkg)
+	}
+
+	pkgs = repository.ApplyOverrides(pkgs, overrides)
+	assert(t, len(pkgs) == 4)
+	assert(t, pkgs[0].Priority == "important")
+	assert(t, pkgs[0].Section == "utils")
+	assert(t, pkgs[0].Values["Section"] == "utils")
+	assert(t, pkgs[0].Maintainer == "Santiago Vila <sanvila@debian.org>")
+	assert(t, pkgs[2].Section == "libdevel")
+	assert(t, pkgs[3].Section == "x11")
+	assert(t, pkgs[3].Maintainer == "Paul Tagliamonte <paultag@debian.org>")
+	assert(t, pkgs[3].Values["Maintainer"] == "Paul Tagliamonte <paultag@debian.org>")
+
+	pkg := control.BinaryIndex{Package: "hello"}
+	repository.ApplyOverrides([]*control.BinaryIndex{&pkg}, overrides)
+	assert(t, pkg.Priority == "important")
+}
+
+// vim: foldmethod=marker
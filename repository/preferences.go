@@ -0,0 +1,244 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+// A Preference is a single stanza of an APT preferences file, such as
+// `/etc/apt/preferences.d/backports`, which pins the packages matching
+// Package to the given PinPriority, if the Pin applies to them.
+type Preference struct {
+	// Space separated list of package names, globs (such as `lib*`) or
+	// regular expressions surrounded by slashes (such as `/^golang-/`).
+	Package string
+
+	// The Pin, such as `release a=bookworm-backports` or `version 2.10*`.
+	Pin string
+
+	PinPriority int
+
+	// Any `Explanation` fields of the stanza, and any comment lines
+	// preceding the stanza, one per line.
+	Explanation string
+}
+
+// Parse an APT preferences file, as documented in apt_preferences(5).
+func ParsePreferences(r io.Reader) ([]*Preference, error) {
+	ret := []*Preference{}
+	scanner := bufio.NewScanner(r)
+
+	comments := []string{}
+	stanza := []string{}
+	flush := func() error {
+		if len(stanza) == 0 {
+			return nil
+		}
+		preference, err := parsePreference(stanza, comments)
+		if err != nil {
+			return err
+		}
+		ret = append(ret, preference)
+		comments = []string{}
+		stanza = []string{}
+		return nil
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(line, "#"):
+			comments = append(comments, strings.TrimSpace(strings.TrimPrefix(line, "#")))
+		default:
+			stanza = append(stanza, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Return the values of every Explanation field in the lines of a stanza,
+// one per line. These are collected from the lines themselves, since a
+// Paragraph only keeps the last value of a repeated field.
+func parseExplanations(stanza []string) []string {
+	ret := []string{}
+	inExplanation := false
+	for _, line := range stanza {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if inExplanation {
+				ret = append(ret, strings.TrimSpace(line))
+			}
+			continue
+		}
+		key, value, _ := strings.Cut(line, ":")
+		inExplanation = strings.EqualFold(strings.TrimSpace(key), "Explanation")
+		if inExplanation {
+			ret = append(ret, strings.TrimSpace(value))
+		}
+	}
+	return ret
+}
+
+func parsePreference(stanza []string, comments []string) (*Preference, error) {
+	text := strings.Join(stanza, "\n") + "\n"
+	reader, err := control.NewParagraphReader(strings.NewReader(text), nil)
+	if err != nil {
+		return nil, err
+	}
+	paragraph, err := reader.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	if missing := control.FieldExists(paragraph, "Package", "Pin", "Pin-Priority"); len(missing) != 0 {
+		return nil, fmt.Errorf("Preference is missing %s", strings.Join(missing, ", "))
+	}
+
+	priority, err := strconv.Atoi(paragraph.Values["Pin-Priority"])
+	if err != nil {
+		return nil, fmt.Errorf("Bad Pin-Priority: '%s'", paragraph.Values["Pin-Priority"])
+	}
+
+	explanation := append(comments, parseExplanations(stanza)...)
+
+	return &Preference{
+		Package:     paragraph.Values["Package"],
+		Pin:         paragraph.Values["Pin"],
+		PinPriority: priority,
+		Explanation: strings.Join(explanation, "\n"),
+	}, nil
+}
+
+// Check to see if the given pattern from the Package field or Pin matches
+// the value, as either a glob or a regular expression in slashes.
+func matchPattern(pattern, value string) bool {
+	if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		return err == nil && re.MatchString(value)
+	}
+	ok, err := path.Match(pattern, value)
+	return err == nil && ok
+}
+
+// Check to see if this Preference applies to the given package, which is
+// in the given Release (which may be nil if unknown).
+//
+// Pins of type `version` are matched against the version of the package,
+// pins of type `release` against the fields of the Release, and pins of
+// type `origin` against the Host of the Release, where `origin ""` matches
+// a local archive.
+func (p *Preference) Matches(pkg *control.BinaryIndex, release *Release) bool {
+	matched := false
+	for _, pattern := range strings.Fields(p.Package) {
+		if matchPattern(pattern, pkg.Package) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+
+	pinType, pin := p.Pin, ""
+	if i := strings.IndexAny(p.Pin, " \t"); i != -1 {
+		pinType, pin = p.Pin[:i], strings.TrimSpace(p.Pin[i:])
+	}
+
+	switch pinType {
+	case "version":
+		return matchPattern(pin, pkg.Version.String())
+	case "release":
+		return release != nil && release.matchesPin(pin)
+	case "origin":
+		return release != nil && strings.EqualFold(strings.Trim(pin, `"`), release.Host)
+	}
+	return false
+}
+
+// Check to see if the Release matches every one of the comma separated
+// `key=value` conditions of a release pin, such as `o=Debian,a=unstable`.
+// A bare value, with no key, is matched against the Version of the Release.
+func (r *Release) matchesPin(pin string) bool {
+	for _, condition := range strings.Split(pin, ",") {
+		condition = strings.TrimSpace(condition)
+		if condition == "" {
+			continue
+		}
+		key, value := "v", condition
+		if i := strings.Index(condition, "="); i != -1 {
+			key, value = condition[:i], condition[i+1:]
+		}
+
+		var candidates []string
+		switch key {
+		case "a":
+			candidates = []string{r.Suite}
+		case "n":
+			candidates = []string{r.Codename}
+		case "o":
+			candidates = []string{r.Origin}
+		case "l":
+			candidates = []string{r.Label}
+		case "v":
+			candidates = []string{r.Version}
+		case "c":
+			candidates = r.Components
+		case "b":
+			for _, arch := range r.Architectures {
+				candidates = append(candidates, arch.String())
+			}
+		default:
+			return false
+		}
+
+		matched := false
+		for _, candidate := range candidates {
+			if matchPattern(value, candidate) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// vim: foldmethod=marker
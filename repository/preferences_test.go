@@ -0,0 +1,88 @@
+package repository_test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/repository"
+	"github.com/akozlenkov/go-debian/version"
+)
+
+/*
+ *
+ */
+
+// Preferences file {{{
+const preferencesFile = `# Prefer the backported toolchain
+Package: golang-* /^gccgo/
+Pin: release a=bookworm-backports, o=Debian*
+Pin-Priority: 500
+
+Explanation: Never upgrade hello past 2.10
+Explanation: since 2.12 breaks the build
+Package: hello
+Pin: version 2.10*
+Pin-Priority: 1001
+
+Package: *
+Pin: origin deb.example.com
+Pin-Priority: -1
+`
+
+// }}}
+
+func TestParsePreferences(t *testing.T) {
+	preferences, err := repository.ParsePreferences(strings.NewReader(preferencesFile))
+	isok(t, err)
+	assert(t, len(preferences) == 3)
+	assert(t, preferences[0].Package == "golang-* /^gccgo/")
+	assert(t, preferences[0].PinPriority == 500)
+	assert(t, preferences[0].Explanation == "Prefer the backported toolchain")
+	assert(t, preferences[1].Pin == "version 2.10*")
+	assert(t, preferences[1].Explanation == "Never upgrade hello past 2.10\nsince 2.12 breaks the build")
+	assert(t, preferences[2].PinPriority == -1)
+	assert(t, preferences[2].Explanation == "")
+
+	_, err = repository.ParsePreferences(strings.NewReader("Package: hello\nPin: version 1\n"))
+	notok(t, err)
+	_, err = repository.ParsePreferences(strings.NewReader("Package: hello\nPin: version 1\nPin-Priority: high\n"))
+	notok(t, err)
+}
+
+func TestPreferenceMatches(t *testing.T) {
+	preferences, err := repository.ParsePreferences(strings.NewReader(preferencesFile))
+	isok(t, err)
+	release, err := repository.ParseRelease(bufio.NewReader(strings.NewReader(releaseFile)))
+	isok(t, err)
+
+	pkg := func(name, ver string) *control.BinaryIndex {
+		v, err := version.Parse(ver)
+		isok(t, err)
+		return &control.BinaryIndex{Package: name, Version: v}
+	}
+
+	assert(t, preferences[0].Matches(pkg("golang-go", "2:1.22~3~bpo12+1"), release))
+	assert(t, preferences[0].Matches(pkg("gccgo-12", "12.2.0-14"), release))
+	assert(t, !preferences[0].Matches(pkg("hello", "2.10-3"), release))
+	assert(t, !preferences[0].Matches(pkg("golang-go", "2:1.22~3~bpo12+1"), nil))
+	assert(t, !preferences[0].Matches(pkg("golang-go", "2:1.19~1"), &repository.Release{
+		Suite:  "bookworm",
+		Origin: "Debian",
+	}))
+
+	assert(t, preferences[1].Matches(pkg("hello", "2.10-3"), nil))
+	assert(t, !preferences[1].Matches(pkg("hello", "2.12-1"), nil))
+
+	assert(t, !preferences[2].Matches(pkg("hello", "2.10-3"), release))
+	assert(t, !preferences[2].Matches(pkg("hello", "2.10-3"), nil))
+	assert(t, preferences[2].Matches(pkg("hello", "2.10-3"), &repository.Release{Host: "deb.example.com"}))
+	assert(t, !preferences[2].Matches(pkg("hello", "2.10-3"), &repository.Release{Host: "deb.debian.org"}))
+
+	local := &repository.Preference{Package: "*", Pin: `origin ""`, PinPriority: 100}
+	assert(t, local.Matches(pkg("hello", "2.10-3"), &repository.Release{}))
+	assert(t, !local.Matches(pkg("hello", "2.10-3"), &repository.Release{Host: "deb.example.com"}))
+}
+
+// vim: foldmethod=marker
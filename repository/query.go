@@ -0,0 +1,94 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"io"
+	"path"
+
+	"github.com/akozlenkov/go-debian/control"
+)
+
+// A PackageQuery describes a filter on the entries of a Packages index.
+// Each non-empty member is matched against the corresponding field of the
+// entry using `path.Match`, so either a literal value or a glob, such as
+// `lib*-dev`, may be used. Empty members match everything.
+type PackageQuery struct {
+	Name         string
+	Version      string
+	Architecture string
+	Section      string
+	Priority     string
+	Maintainer   string
+}
+
+// Check to see if the given BinaryIndex entry satisfies every member of
+// the PackageQuery.
+func (q PackageQuery) Matches(pkg control.BinaryIndex) (bool, error) {
+	for _, el := range [][2]string{
+		{q.Name, pkg.Package},
+		{q.Version, pkg.Version.String()},
+		{q.Architecture, pkg.Architecture.String()},
+		{q.Section, pkg.Section},
+		{q.Priority, pkg.Priority},
+		{q.Maintainer, pkg.Maintainer},
+	} {
+		pattern, value := el[0], el[1]
+		if pattern == "" {
+			continue
+		}
+		ok, err := path.Match(pattern, value)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Read a Packages index from the given reader, and return the entries
+// matching the PackageQuery. Entries are decoded one at a time, so the
+// index is never held in memory in full.
+func QueryPackages(reader io.Reader, q PackageQuery) ([]control.BinaryIndex, error) {
+	decoder, err := control.NewDecoder(reader, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []control.BinaryIndex{}
+	for {
+		pkg := control.BinaryIndex{}
+		if err := decoder.Decode(&pkg); err == io.EOF {
+			return ret, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		ok, err := q.Matches(pkg)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ret = append(ret, pkg)
+		}
+	}
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/repository"
+)
+
+/*
+ *
+ */
+
+// Packages index {{{
+const packagesIndex = `Package: hello
+Version: 2.10-2
+Architecture: amd64
+Maintainer: Santiago Vila <sanvila@debian.org>
+Section: devel
+Priority: optional
+
+Package: libhello-dev
+Source: hello
+Version: 2.10-2
+Architecture: amd64
+Maintainer: Santiago Vila <sanvila@debian.org>
+Section: libdevel
+Priority: optional
+
+Package: libhello-dev
+Source: hello
+Version: 2.10-2
+Architecture: i386
+Maintainer: Santiago Vila <sanvila@debian.org>
+Section: libdevel
+Priority: optional
+
+Package: fbautostart
+Version: 2.718281828-1
+Architecture: amd64
+Maintainer: Paul Tagliamonte <paultag@ubuntu.com>
+Section: misc
+Priority: extra
+`
+
+// }}}
+
+func TestQueryPackages(t *testing.T) {
+	pkgs, err := repository.QueryPackages(strings.NewReader(packagesIndex), repository.PackageQuery{})
+	isok(t, err)
+	assert(t, len(pkgs) == 4)
+
+	pkgs, err = repository.QueryPackages(strings.NewReader(packagesIndex), repository.PackageQuery{
+		Name:         "lib*-dev",
+		Architecture: "amd64",
+	})
+	isok(t, err)
+	assert(t, len(pkgs) == 1)
+	assert(t, pkgs[0].Package == "libhello-dev")
+	assert(t, pkgs[0].Architecture.CPU == "amd64")
+
+	pkgs, err = repository.QueryPackages(strings.NewReader(packagesIndex), repository.PackageQuery{
+		Maintainer: "Paul Tagliamonte *",
+		Version:    "2.718281828-1",
+	})
+	isok(t, err)
+	assert(t, len(pkgs) == 1)
+	assert(t, pkgs[0].Package == "fbautostart")
+
+	_, err = repository.QueryPackages(strings.NewReader(packagesIndex), repository.PackageQuery{
+		Name: "[",
+	})
+	notok(t, err)
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,180 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/dependency"
+)
+
+// The Release struct represents the Release (or InRelease) file at the top
+// of each suite of a Debian (or Debian derived) archive, such as
+// dists/unstable/Release. It describes the suite, and lists the checksums
+// of all the index files the suite contains.
+type Release struct {
+	control.Paragraph
+
+	Origin        string
+	Label         string
+	Suite         string
+	Codename      string
+	Version       string
+	Date          string
+	ValidUntil    string `control:"Valid-Until"`
+	Architectures []dependency.Arch
+	Components    []string
+	Description   string
+	AcquireByHash bool `control:"Acquire-By-Hash"`
+
+	MD5Sum []control.MD5FileHash    `control:"MD5Sum" delim:"\n" strip:"\n\r\t "`
+	SHA1   []control.SHA1FileHash   `control:"SHA1" delim:"\n" strip:"\n\r\t "`
+	SHA256 []control.SHA256FileHash `control:"SHA256" delim:"\n" strip:"\n\r\t "`
+	SHA512 []control.SHA512FileHash `control:"SHA512" delim:"\n" strip:"\n\r\t "`
+
+	// The time at which the Release was parsed, which can be used to
+	// decide when a cached copy of the Release needs to be fetched again.
+	FetchedAt time.Time `control:"-"`
+
+	// The hostname of the archive the Release was fetched from, such as
+	// `deb.debian.org`, which `origin` pins in APT preferences are matched
+	// against. This isn't part of the Release file, so it's up to the
+	// caller to set it, and it's empty for a local archive.
+	Host string `control:"-"`
+}
+
+// ErrFileNotInRelease is returned when looking up the checksum of a file
+// which isn't listed in the Release.
+var ErrFileNotInRelease = errors.New("file not listed in Release")
+
+// Given a path on the filesystem, Parse the file off the disk and return
+// a pointer to a brand new Release struct, unless error is set to a value
+// other than nil.
+func ParseReleaseFile(path string) (*Release, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseRelease(bufio.NewReader(f))
+}
+
+// Given a bufio.Reader, consume the Reader, and return a Release object
+// for use. If the Release is clearsigned (as an InRelease file is), the
+// signature is *not* checked.
+func ParseRelease(reader *bufio.Reader) (*Release, error) {
+	ret := Release{}
+	if err := control.Unmarshal(&ret, reader); err != nil {
+		return nil, err
+	}
+	ret.FetchedAt = time.Now()
+	return &ret, nil
+}
+
+// Check to see if the Release was fetched more than maxAge ago, and should
+// be fetched again.
+func (r *Release) IsStale(maxAge time.Duration) bool {
+	return time.Since(r.FetchedAt) > maxAge
+}
+
+// Return the hex encoded hash and the size of the given file (such as
+// `main/binary-amd64/Packages.xz`, relative to the Release) from the
+// checksum list of the given algorithm, which is one of `md5` (or `MD5Sum`),
+// `sha1`, `sha256` or `sha512`, in any case. If the file isn't listed,
+// ErrFileNotInRelease is returned.
+func (r *Release) HashForFile(relativePath string, algorithm string) (string, int64, error) {
+	hashes := []control.FileHash{}
+	switch strings.ToLower(algorithm) {
+	case "md5", "md5sum":
+		for _, hash := range r.MD5Sum {
+			hashes = append(hashes, hash.FileHash)
+		}
+	case "sha1":
+		for _, hash := range r.SHA1 {
+			hashes = append(hashes, hash.FileHash)
+		}
+	case "sha256":
+		for _, hash := range r.SHA256 {
+			hashes = append(hashes, hash.FileHash)
+		}
+	case "sha512":
+		for _, hash := range r.SHA512 {
+			hashes = append(hashes, hash.FileHash)
+		}
+	default:
+		return "", 0, fmt.Errorf("Unknown checksum algorithm '%s'", algorithm)
+	}
+
+	relativePath = strings.TrimPrefix(relativePath, "/")
+	for _, hash := range hashes {
+		if hash.Filename == relativePath {
+			return hash.Hash, hash.Size, nil
+		}
+	}
+	return "", 0, fmt.Errorf("%w: '%s' (%s)", ErrFileNotInRelease, relativePath, algorithm)
+}
+
+func (r *Release) isSet(field string) bool {
+	return strings.EqualFold(strings.TrimSpace(r.Values[field]), "yes")
+}
+
+// Check to see if the suite is marked with `NotAutomatic: yes`, which tells
+// APT never to select packages from it automatically, as is done for
+// experimental and backports.
+func (r *Release) NotAutomatic() bool {
+	return r.isSet("NotAutomatic")
+}
+
+// Check to see if the suite is marked with `ButAutomaticUpgrades: yes`,
+// which tells APT to upgrade packages already installed from a NotAutomatic
+// suite automatically.
+func (r *Release) ButAutomaticUpgrades() bool {
+	return r.isSet("ButAutomaticUpgrades")
+}
+
+// Return the first of the candidate architectures (in order of preference)
+// which this suite has packages for, or false if the suite doesn't support
+// any of them.
+func (r *Release) PreferredArch(candidates []dependency.Arch) (dependency.Arch, bool) {
+	for _, candidate := range candidates {
+		for _, arch := range r.Architectures {
+			if candidate.Is(&arch) {
+				return candidate, true
+			}
+		}
+	}
+	return dependency.Arch{}, false
+}
+
+// vim: foldmethod=marker
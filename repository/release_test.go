@@ -0,0 +1,129 @@
+package repository_test
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akozlenkov/go-debian/dependency"
+	"github.com/akozlenkov/go-debian/repository"
+)
+
+/*
+ *
+ */
+
+// Release file {{{
+const releaseFile = `Origin: Debian Backports
+Label: Debian Backports
+Suite: bookworm-backports
+Codename: bookworm-backports
+Changelogs: https://metadata.ftp-master.debian.org/changelogs/@CHANGEPATH@_changelog
+Date: Sat, 10 Aug 2024 20:04:42 UTC
+Valid-Until: Sat, 17 Aug 2024 20:04:42 UTC
+NotAutomatic: yes
+ButAutomaticUpgrades: Yes
+Acquire-By-Hash: yes
+No-Support-for-Architecture-all: Packages
+Architectures: all amd64 arm64 i386
+Components: main contrib non-free-firmware non-free
+Description: Backports for the Debian 12 release
+MD5Sum:
+ 0c7cab6d538ba5a2ce3d2ac8cb1f82a6   1232839 contrib/Contents-all
+ 24a4d4a5e2bcd3ab3fc4ea5e79b1e8b7    104731 main/binary-amd64/Packages.xz
+SHA256:
+ 3fa1cb8ed6fa5d1373c97384ff2f4a7a0b5d5bc6a9f2bb8b4e6ed2de4e399b7e   1232839 contrib/Contents-all
+ 9e5b0c8b94a4b56fbd7c2b0d3e46e181e51cb13638e1a6cd2cc4f0e0b9e4b1c7    104731 main/binary-amd64/Packages.xz
+`
+
+// }}}
+
+func TestReleaseParse(t *testing.T) {
+	release, err := repository.ParseRelease(bufio.NewReader(strings.NewReader(releaseFile)))
+	isok(t, err)
+	assert(t, release.Suite == "bookworm-backports")
+	assert(t, release.ValidUntil == "Sat, 17 Aug 2024 20:04:42 UTC")
+	assert(t, release.AcquireByHash)
+	assert(t, len(release.Architectures) == 4)
+	assert(t, release.Architectures[1].CPU == "amd64")
+	assert(t, len(release.Components) == 4)
+	assert(t, release.Components[2] == "non-free-firmware")
+	assert(t, len(release.MD5Sum) == 2)
+	assert(t, len(release.SHA256) == 2)
+	assert(t, release.SHA256[1].Filename == "main/binary-amd64/Packages.xz")
+	assert(t, release.SHA256[1].Size == 104731)
+}
+
+func TestReleaseNotAutomatic(t *testing.T) {
+	release, err := repository.ParseRelease(bufio.NewReader(strings.NewReader(releaseFile)))
+	isok(t, err)
+	assert(t, release.NotAutomatic())
+	assert(t, release.ButAutomaticUpgrades())
+
+	release, err = repository.ParseRelease(bufio.NewReader(strings.NewReader(`Suite: unstable
+NotAutomatic: no
+`)))
+	isok(t, err)
+	assert(t, !release.NotAutomatic())
+	assert(t, !release.ButAutomaticUpgrades())
+}
+
+func TestReleasePreferredArch(t *testing.T) {
+	release, err := repository.ParseRelease(bufio.NewReader(strings.NewReader(releaseFile)))
+	isok(t, err)
+
+	arches, err := dependency.ParseArchitectures("riscv64 arm64 amd64")
+	isok(t, err)
+	arch, ok := release.PreferredArch(arches)
+	assert(t, ok)
+	assert(t, arch.CPU == "arm64")
+
+	arches, err = dependency.ParseArchitectures("riscv64 kfreebsd-amd64")
+	isok(t, err)
+	_, ok = release.PreferredArch(arches)
+	assert(t, !ok)
+
+	_, ok = release.PreferredArch(nil)
+	assert(t, !ok)
+}
+
+func TestReleaseIsStale(t *testing.T) {
+	before := time.Now()
+	release, err := repository.ParseRelease(bufio.NewReader(strings.NewReader(releaseFile)))
+	isok(t, err)
+	assert(t, !release.FetchedAt.Before(before))
+	assert(t, !release.IsStale(time.Hour))
+
+	release.FetchedAt = time.Now().Add(-2 * time.Hour)
+	assert(t, release.IsStale(time.Hour))
+	assert(t, !release.IsStale(3*time.Hour))
+}
+
+func TestReleaseHashForFile(t *testing.T) {
+	release, err := repository.ParseRelease(bufio.NewReader(strings.NewReader(releaseFile)))
+	isok(t, err)
+
+	hash, size, err := release.HashForFile("main/binary-amd64/Packages.xz", "SHA256")
+	isok(t, err)
+	assert(t, hash == "9e5b0c8b94a4b56fbd7c2b0d3e46e181e51cb13638e1a6cd2cc4f0e0b9e4b1c7")
+	assert(t, size == 104731)
+
+	hash, size, err = release.HashForFile("/contrib/Contents-all", "MD5Sum")
+	isok(t, err)
+	assert(t, hash == "0c7cab6d538ba5a2ce3d2ac8cb1f82a6")
+	assert(t, size == 1232839)
+
+	_, _, err = release.HashForFile("main/binary-i386/Packages.xz", "sha256")
+	assert(t, errors.Is(err, repository.ErrFileNotInRelease))
+
+	_, _, err = release.HashForFile("main/binary-amd64/Packages.xz", "sha512")
+	assert(t, errors.Is(err, repository.ErrFileNotInRelease))
+
+	_, _, err = release.HashForFile("main/binary-amd64/Packages.xz", "crc32")
+	notok(t, err)
+	assert(t, !errors.Is(err, repository.ErrFileNotInRelease))
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,124 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/openpgp"
+)
+
+var fingerprintRegexp = regexp.MustCompile(`^[0-9A-Fa-f]{40}$`)
+
+// Parse the value of the `Signed-By` field of a deb822 style APT source,
+// which is either the fingerprint of a key (40 hex characters, with no
+// whitespace), in which case the fingerprint is returned in uppercase, or
+// an inline ASCII armored key block (starting with `-----BEGIN`), in which
+// case the armored key is returned as keyData.
+func ParseSignedBy(value string) (fingerprint string, keyData []byte, err error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(value, "-----BEGIN"):
+		return "", []byte(value + "\n"), nil
+	case fingerprintRegexp.MatchString(value):
+		return strings.ToUpper(value), nil, nil
+	default:
+		return "", nil, fmt.Errorf("Invalid Signed-By value '%s'", value)
+	}
+}
+
+// Resolve the value of the `Signed-By` field of a deb822 style APT source
+// to the keys it names. An inline armored key block is read directly, the
+// absolute path of a keyring file is read from disk, and a fingerprint is
+// looked up in the `.gpg` and `.asc` keyrings in keyringDir (such as
+// `/etc/apt/keyrings`), matching either a primary key or a subkey.
+func LoadSignedByKey(signingKey string, keyringDir string) (openpgp.EntityList, error) {
+	signingKey = strings.TrimSpace(signingKey)
+	if filepath.IsAbs(signingKey) {
+		return readKeyringFile(signingKey)
+	}
+
+	fingerprint, keyData, err := ParseSignedBy(signingKey)
+	if err != nil {
+		return nil, err
+	}
+	if keyData != nil {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(keyData))
+	}
+
+	paths, err := filepath.Glob(filepath.Join(keyringDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	ret := openpgp.EntityList{}
+	for _, path := range paths {
+		if ext := filepath.Ext(path); ext != ".gpg" && ext != ".asc" {
+			continue
+		}
+		keyring, err := readKeyringFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entity := range keyring {
+			if entityHasFingerprint(entity, fingerprint) {
+				ret = append(ret, entity)
+			}
+		}
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("No key with fingerprint '%s' in '%s'", fingerprint, keyringDir)
+	}
+	return ret, nil
+}
+
+// Read a keyring file, which is ASCII armored if its name ends in `.asc`,
+// and in the binary OpenPGP format otherwise.
+func readKeyringFile(path string) (openpgp.EntityList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(path, ".asc") {
+		return openpgp.ReadArmoredKeyRing(f)
+	}
+	return openpgp.ReadKeyRing(f)
+}
+
+func entityHasFingerprint(entity *openpgp.Entity, fingerprint string) bool {
+	if fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint[:]) == fingerprint {
+		return true
+	}
+	for _, subkey := range entity.Subkeys {
+		if fmt.Sprintf("%X", subkey.PublicKey.Fingerprint[:]) == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// vim: foldmethod=marker
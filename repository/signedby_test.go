@@ -0,0 +1,87 @@
+package repository_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/repository"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+/*
+ *
+ */
+
+func newSigningKey(t *testing.T) (*openpgp.Entity, string) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Test Archive Key", "testing only", "archive@example.com", nil)
+	isok(t, err)
+
+	armored := bytes.Buffer{}
+	writer, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	isok(t, err)
+	isok(t, entity.Serialize(writer))
+	isok(t, writer.Close())
+	return entity, armored.String()
+}
+
+func TestParseSignedBy(t *testing.T) {
+	fingerprint, keyData, err := repository.ParseSignedBy(" b8b80b5b623eab6ad8775c45b7c3b95b8da28402\n")
+	isok(t, err)
+	assert(t, fingerprint == "B8B80B5B623EAB6AD8775C45B7C3B95B8DA28402")
+	assert(t, keyData == nil)
+
+	_, armored := newSigningKey(t)
+	fingerprint, keyData, err = repository.ParseSignedBy(armored)
+	isok(t, err)
+	assert(t, fingerprint == "")
+	assert(t, strings.HasPrefix(string(keyData), "-----BEGIN PGP PUBLIC KEY BLOCK-----"))
+
+	for _, value := range []string{
+		"",
+		"B8B80B5B623EAB6AD8775C45B7C3B95B8DA2840",
+		"B8B8 0B5B 623E AB6A D877 5C45 B7C3 B95B 8DA2 8402",
+		"/usr/share/keyrings/debian-archive-keyring.gpg",
+	} {
+		_, _, err = repository.ParseSignedBy(value)
+		notok(t, err)
+	}
+}
+
+func TestLoadSignedByKey(t *testing.T) {
+	entity, armored := newSigningKey(t)
+	other, _ := newSigningKey(t)
+	fingerprint := fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint[:])
+
+	keyring, err := repository.LoadSignedByKey(armored, "")
+	isok(t, err)
+	assert(t, len(keyring) == 1)
+	assert(t, keyring[0].PrimaryKey.KeyId == entity.PrimaryKey.KeyId)
+
+	dir := t.TempDir()
+	isok(t, os.WriteFile(filepath.Join(dir, "archive.asc"), []byte(armored), 0644))
+	binary := bytes.Buffer{}
+	isok(t, other.Serialize(&binary))
+	isok(t, os.WriteFile(filepath.Join(dir, "other.gpg"), binary.Bytes(), 0644))
+	isok(t, os.WriteFile(filepath.Join(dir, "README"), []byte("not a keyring"), 0644))
+
+	keyring, err = repository.LoadSignedByKey(strings.ToLower(fingerprint), dir)
+	isok(t, err)
+	assert(t, len(keyring) == 1)
+	assert(t, keyring[0].PrimaryKey.KeyId == entity.PrimaryKey.KeyId)
+
+	keyring, err = repository.LoadSignedByKey(filepath.Join(dir, "other.gpg"), "")
+	isok(t, err)
+	assert(t, len(keyring) == 1)
+	assert(t, keyring[0].PrimaryKey.KeyId == other.PrimaryKey.KeyId)
+
+	_, err = repository.LoadSignedByKey(strings.Repeat("0", 40), dir)
+	notok(t, err)
+}
+
+// vim: foldmethod=marker
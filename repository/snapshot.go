@@ -0,0 +1,64 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The URL of the Debian archive on snapshot.debian.org, for use as the
+// baseURL argument of SnapshotURL and SnapshotRelease.
+const DebianSnapshotURL = "https://snapshot.debian.org/archive/debian"
+
+// The layout of the timestamp used by snapshot.debian.org in its URLs.
+const snapshotLayout = "20060102T150405Z"
+
+// Return the URL of the snapshot of the archive at baseURL (such as
+// DebianSnapshotURL) at the given time. This is the root of the archive,
+// containing the `dists` and `pool` directories.
+func SnapshotURL(baseURL string, at time.Time) string {
+	return fmt.Sprintf("%s/%s", strings.TrimRight(baseURL, "/"), at.UTC().Format(snapshotLayout))
+}
+
+// Fetch and parse the InRelease file of the given suite, as it was in the
+// snapshot of the archive at baseURL (such as DebianSnapshotURL) at the
+// given time. The OpenPGP signature of the InRelease file is *not* checked.
+func SnapshotRelease(baseURL, suite string, at time.Time) (*Release, error) {
+	url := fmt.Sprintf("%s/dists/%s/InRelease", SnapshotURL(baseURL, at), suite)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to fetch '%s': %s", url, resp.Status)
+	}
+
+	return ParseRelease(bufio.NewReader(resp.Body))
+}
+
+// vim: foldmethod=marker
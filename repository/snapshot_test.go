@@ -0,0 +1,43 @@
+package repository_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/akozlenkov/go-debian/repository"
+)
+
+/*
+ *
+ */
+
+func TestSnapshotURL(t *testing.T) {
+	at := time.Date(2024, time.August, 10, 22, 4, 42, 0, time.FixedZone("CEST", 2*60*60))
+	assert(t, repository.SnapshotURL(repository.DebianSnapshotURL, at) ==
+		"https://snapshot.debian.org/archive/debian/20240810T200442Z")
+	assert(t, repository.SnapshotURL("http://localhost/archive/", at) ==
+		"http://localhost/archive/20240810T200442Z")
+}
+
+func TestSnapshotRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/archive/debian/20240810T200442Z/dists/bookworm-backports/InRelease" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(releaseFile))
+	}))
+	defer server.Close()
+
+	at := time.Date(2024, time.August, 10, 20, 4, 42, 0, time.UTC)
+	release, err := repository.SnapshotRelease(server.URL+"/archive/debian", "bookworm-backports", at)
+	isok(t, err)
+	assert(t, release.Codename == "bookworm-backports")
+
+	_, err = repository.SnapshotRelease(server.URL+"/archive/debian", "bookworm", at)
+	notok(t, err)
+}
+
+// vim: foldmethod=marker
@@ -0,0 +1,167 @@
+/* {{{ Copyright (c) Paul R. Tagliamonte <paultag@debian.org>, 2015
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in
+ * all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ * THE SOFTWARE. }}} */
+
+package repository // import "github.com/akozlenkov/go-debian/repository"
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/dependency"
+	"github.com/akozlenkov/go-debian/version"
+)
+
+// A package (or virtual package) name, as satisfied by a particular binary
+// package. For a real package, the version is the version of the package,
+// for a virtual package it's the version given in the Provides field, which
+// is nil if the Provides wasn't versioned.
+type solverEntry struct {
+	pkg     *control.BinaryIndex
+	version *version.Version
+}
+
+type solverIndex map[string][]solverEntry
+
+func (index solverIndex) add(pkg *control.BinaryIndex) {
+	ver := pkg.Version
+	index[pkg.Package] = append(index[pkg.Package], solverEntry{pkg: pkg, version: &ver})
+
+	provides, err := dependency.Parse(pkg.Values["Provides"])
+	if err != nil {
+		return
+	}
+	for _, possi := range provides.GetAllPossibilities() {
+		entry := solverEntry{pkg: pkg}
+		if possi.Version != nil && possi.Version.Operator == "=" {
+			if ver, err := version.Parse(possi.Version.Number); err == nil {
+				entry.version = &ver
+			}
+		}
+		index[possi.Name] = append(index[possi.Name], entry)
+	}
+}
+
+// Return all the packages which satisfy the Possibility.
+func (index solverIndex) satisfying(possi dependency.Possibility) []*control.BinaryIndex {
+	ret := []*control.BinaryIndex{}
+	for _, entry := range index[possi.Name] {
+		if possi.Version == nil || (entry.version != nil && possi.Version.SatisfiedBy(*entry.version)) {
+			ret = append(ret, entry.pkg)
+		}
+	}
+	return ret
+}
+
+func newSolverIndex(pkgs []*control.BinaryIndex) solverIndex {
+	index := solverIndex{}
+	for _, pkg := range pkgs {
+		index.add(pkg)
+	}
+	return index
+}
+
+type solver struct {
+	available solverIndex
+	installed solverIndex
+	chosen    solverIndex
+	ret       []string
+}
+
+// Make sure one of the Possibilities of the relation is satisfied, either
+// by something already installed, or something already chosen, or failing
+// that, by choosing the newest available package which satisfies the first
+// Possibility it can.
+func (s *solver) satisfy(relation dependency.Relation, from string) error {
+	for _, possi := range relation.Possibilities {
+		if possi.Substvar {
+			return nil
+		}
+		if len(s.installed.satisfying(possi)) != 0 || len(s.chosen.satisfying(possi)) != 0 {
+			return nil
+		}
+	}
+
+	for _, possi := range relation.Possibilities {
+		candidates := s.available.satisfying(possi)
+		if len(candidates) == 0 {
+			continue
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return version.Compare(candidates[i].Version, candidates[j].Version) > 0
+		})
+		return s.install(candidates[0])
+	}
+
+	return fmt.Errorf("Unsatisfiable dependency '%s' of '%s'", relation, from)
+}
+
+func (s *solver) install(pkg *control.BinaryIndex) error {
+	s.chosen.add(pkg)
+	s.ret = append(s.ret, pkg.Package)
+
+	depends := pkg.GetPreDepends()
+	depends.Relations = append(depends.Relations, pkg.GetDepends().Relations...)
+	for _, relation := range depends.Relations {
+		if err := s.satisfy(relation, pkg.Package); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Work out which packages need to be installed, in addition to the
+// installed packages, in order to install the start packages, and satisfy
+// all of their (Pre-)Depends, as well as the Depends of those packages,
+// and so on. Each of the start packages may be a plain package name, or a
+// relation such as `hello (>= 2.10)` or `mawk | awk`.
+//
+// This is a very simple solver, suitable for scripts and tests, and not a
+// replacement for APT. When a relation isn't already satisfied, the newest
+// available version of the first alternative which can be satisfied is
+// picked, and never reconsidered. Virtual packages are satisfied by
+// anything which Provides them. Conflicts, Breaks and architecture
+// restrictions are not considered at all.
+//
+// The names of the packages to be installed are returned in the order they
+// were picked. If any relation can't be satisfied, an error is returned.
+func SolveDeps(start []string, available []*control.BinaryIndex, installed []*control.BinaryIndex) ([]string, error) {
+	s := solver{
+		available: newSolverIndex(available),
+		installed: newSolverIndex(installed),
+		chosen:    solverIndex{},
+		ret:       []string{},
+	}
+
+	for _, name := range start {
+		relations, err := dependency.Parse(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, relation := range relations.Relations {
+			if err := s.satisfy(relation, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s.ret, nil
+}
+
+// vim: foldmethod=marker
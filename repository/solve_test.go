@@ -0,0 +1,97 @@
+package repository_test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/akozlenkov/go-debian/control"
+	"github.com/akozlenkov/go-debian/repository"
+)
+
+/*
+ *
+ */
+
+// Solver Packages files {{{
+const solverAvailable = `Package: hello
+Version: 2.10-3
+Depends: libc6 (>= 2.34), hello-data | hello-data-minimal, ${misc:Depends}
+
+Package: hello
+Version: 2.10-2
+Depends: libc6 (>= 2.14)
+
+Package: hello-data
+Version: 2.10-3
+Depends: awk (>= 1:3.1)
+
+Package: libc6
+Version: 2.36-9
+Depends: libgcc-s1
+
+Package: libgcc-s1
+Version: 12.2.0-14
+
+Package: mawk
+Version: 1.3.4.20200120-3.1
+Provides: awk
+
+Package: gawk
+Version: 1:5.2.1-2
+Provides: awk (= 1:5.2.1-2)
+
+Package: broken
+Version: 1.0-1
+Depends: libc6 (>= 3.0)
+`
+
+const solverInstalled = `Package: libgcc-s1
+Version: 12.2.0-14
+
+Package: libc6
+Version: 2.31-13
+Depends: libgcc-s1
+`
+
+// }}}
+
+func parseSolverPackages(t *testing.T, index string) []*control.BinaryIndex {
+	t.Helper()
+	pkgs, err := control.ParseBinaryIndex(bufio.NewReader(strings.NewReader(index)))
+	isok(t, err)
+	ret := []*control.BinaryIndex{}
+	for i := range pkgs {
+		ret = append(ret, &pkgs[i])
+	}
+	return ret
+}
+
+func TestSolveDeps(t *testing.T) {
+	available := parseSolverPackages(t, solverAvailable)
+	installed := parseSolverPackages(t, solverInstalled)
+
+	pkgs, err := repository.SolveDeps([]string{"hello"}, available, installed)
+	isok(t, err)
+	assert(t, strings.Join(pkgs, " ") == "hello libc6 hello-data gawk")
+
+	pkgs, err = repository.SolveDeps([]string{"hello (<< 2.10-3)"}, available, installed)
+	isok(t, err)
+	assert(t, strings.Join(pkgs, " ") == "hello")
+
+	pkgs, err = repository.SolveDeps([]string{"mawk | gawk", "awk"}, available, nil)
+	isok(t, err)
+	assert(t, strings.Join(pkgs, " ") == "mawk")
+
+	pkgs, err = repository.SolveDeps([]string{"libc6"}, available, installed)
+	isok(t, err)
+	assert(t, len(pkgs) == 0)
+
+	_, err = repository.SolveDeps([]string{"broken"}, available, installed)
+	notok(t, err)
+
+	_, err = repository.SolveDeps([]string{"goodbye"}, available, installed)
+	notok(t, err)
+}
+
+// vim: foldmethod=marker
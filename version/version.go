@@ -67,6 +67,56 @@ func (v *Version) IsNative() bool {
 	return len(v.Revision) == 0
 }
 
+// UpstreamVersion returns the upstream part of the version, without the
+// epoch or the Debian revision, such as "2.10" for 1:2.10-3.
+func (v Version) UpstreamVersion() string {
+	return v.Version
+}
+
+// DebianRevision returns the Debian revision of the version, such as
+// "1ubuntu2+deb12u1" for 2.10-1ubuntu2+deb12u1, or an empty string for a
+// native package.
+func (v Version) DebianRevision() string {
+	return v.Revision
+}
+
+// upstreamMajor returns the first component of the upstream version, up to
+// the first `.`, `~` or `+`, such as "2" for 2.10~rc1+dfsg.
+func (v Version) upstreamMajor() string {
+	if end := strings.IndexAny(v.Version, ".~+"); end != -1 {
+		return v.Version[:end]
+	}
+	return v.Version
+}
+
+// CompatibleWith returns true if both versions have the same epoch, and the
+// same upstream major version (the first dotted component of the upstream
+// version), so 1:2.10-3 is compatible with 1:2.12-1, but not with 1:3.0-1
+// or 2.10-3. This is only a heuristic for guessing at API compatibility,
+// since plenty of upstreams don't follow semantic versioning.
+func (v Version) CompatibleWith(other Version) bool {
+	return v.Epoch == other.Epoch && v.upstreamMajor() == other.upstreamMajor()
+}
+
+// IsPreRelease returns true if either the upstream version or the revision
+// contains a tilde, which sorts before anything else, making 1.0~rc1 a
+// pre-release of 1.0.
+func (v Version) IsPreRelease() bool {
+	return strings.Contains(v.Version, "~") || strings.Contains(v.Revision, "~")
+}
+
+// PreReleaseTag returns the part of the version following the last tilde,
+// such as "rc1" for 1.0~rc1, or an empty string if there is none. A tilde
+// in the revision takes precedence over one in the upstream version.
+func (v Version) PreReleaseTag() string {
+	for _, part := range []string{v.Revision, v.Version} {
+		if tilde := strings.LastIndex(part, "~"); tilde != -1 {
+			return part[tilde+1:]
+		}
+	}
+	return ""
+}
+
 func (version *Version) MarshalText() ([]byte, error) {
 	return json.Marshal(version.String())
 }
@@ -196,6 +246,140 @@ func Compare(a Version, b Version) int {
 	return verrevcmp(a.Revision, b.Revision)
 }
 
+// ExplainCompare parses and compares the two provided Debian versions, just
+// like Compare, and also returns a human readable, step by step explanation
+// of how the result was reached: first the epochs are compared, then the
+// upstream versions, and then the revisions, each of which is compared as
+// alternating runs of non-digits and digits. The result is -1, 0 or 1.
+func ExplainCompare(a, b string) (int, string, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, "", fmt.Errorf("%s: %v", a, err)
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, "", fmt.Errorf("%s: %v", b, err)
+	}
+
+	out := strings.Builder{}
+	result := explainCompare(va, vb, &out)
+	switch {
+	case result < 0:
+		fmt.Fprintf(&out, "%s < %s\n", va, vb)
+	case result > 0:
+		fmt.Fprintf(&out, "%s > %s\n", va, vb)
+	default:
+		fmt.Fprintf(&out, "%s == %s\n", va, vb)
+	}
+	return result, out.String(), nil
+}
+
+func explainCompare(a, b Version, out *strings.Builder) int {
+	fmt.Fprintf(out, "epoch: %d %s %d\n", a.Epoch, relation(cmpUint(a.Epoch, b.Epoch)), b.Epoch)
+	if rc := cmpUint(a.Epoch, b.Epoch); rc != 0 {
+		return rc
+	}
+
+	fmt.Fprintf(out, "upstream version: %q vs %q\n", a.Version, b.Version)
+	if rc := explainVerrevcmp(a.Version, b.Version, out); rc != 0 {
+		return rc
+	}
+
+	fmt.Fprintf(out, "revision: %q vs %q\n", a.Revision, b.Revision)
+	return explainVerrevcmp(a.Revision, b.Revision, out)
+}
+
+// explainVerrevcmp compares a and b the same way verrevcmp does, one run of
+// non-digits and digits at a time, writing out each step.
+func explainVerrevcmp(a, b string, out *strings.Builder) int {
+	for len(a) > 0 || len(b) > 0 {
+		var ap, bp string
+		ap, a = splitRun(a, false)
+		bp, b = splitRun(b, false)
+		rc := 0
+		for i := 0; i < len(ap) || i < len(bp); i++ {
+			ac, bc := 0, 0
+			if i < len(ap) {
+				ac = order(rune(ap[i]))
+			}
+			if i < len(bp) {
+				bc = order(rune(bp[i]))
+			}
+			if ac != bc {
+				rc = sign(ac - bc)
+				break
+			}
+		}
+		fmt.Fprintf(out, "  non-digits: %q %s %q\n", ap, relation(rc), bp)
+		if rc != 0 {
+			return rc
+		}
+
+		ap, a = splitRun(a, true)
+		bp, b = splitRun(b, true)
+		an, bn := strings.TrimLeft(ap, "0"), strings.TrimLeft(bp, "0")
+		if len(an) != len(bn) {
+			rc = sign(len(an) - len(bn))
+		} else {
+			rc = strings.Compare(an, bn)
+		}
+		if ap == "" && bp == "" {
+			continue
+		}
+		fmt.Fprintf(out, "  digits: %s %s %s\n", orZero(ap), relation(rc), orZero(bp))
+		if rc != 0 {
+			return rc
+		}
+	}
+	return 0
+}
+
+// splitRun splits the leading run of digits (or non-digits) off of s.
+func splitRun(s string, digits bool) (string, string) {
+	i := 0
+	for i < len(s) && cisdigit(rune(s[i])) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+func cmpUint(a, b uint) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	}
+	return 0
+}
+
+func relation(rc int) string {
+	switch {
+	case rc < 0:
+		return "<"
+	case rc > 0:
+		return ">"
+	}
+	return "=="
+}
+
 // Parse returns a Version struct filled with the epoch, version and revision
 // specified in input. It verifies the version string as a whole, just like
 // dpkg(1), and even returns roughly the same error messages.
@@ -204,6 +388,24 @@ func Parse(input string) (Version, error) {
 	return result, parseInto(&result, input)
 }
 
+// Split the epoch off the front of a version string, such as `1:2.10-2`,
+// returning the epoch and the rest of the version string. If there is no
+// epoch, this returns 0 and the version string unchanged.
+func ParseEpoch(s string) (epoch int, rest string, err error) {
+	colon := strings.Index(s, ":")
+	if colon == -1 {
+		return 0, s, nil
+	}
+	parsed, err := strconv.ParseInt(s[:colon], 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("epoch: %v", err)
+	}
+	if parsed < 0 {
+		return 0, "", fmt.Errorf("epoch in version is negative")
+	}
+	return int(parsed), s[colon+1:], nil
+}
+
 func parseInto(result *Version, input string) error {
 	trimmed := strings.TrimSpace(input)
 	if trimmed == "" {
@@ -214,19 +416,13 @@ func parseInto(result *Version, input string) error {
 		return fmt.Errorf("version string has embedded spaces")
 	}
 
-	colon := strings.Index(trimmed, ":")
-	if colon != -1 {
-		epoch, err := strconv.ParseInt(trimmed[:colon], 10, 64)
-		if err != nil {
-			return fmt.Errorf("epoch: %v", err)
-		}
-		if epoch < 0 {
-			return fmt.Errorf("epoch in version is negative")
-		}
-		result.Epoch = uint(epoch)
+	epoch, rest, err := ParseEpoch(trimmed)
+	if err != nil {
+		return err
 	}
+	result.Epoch = uint(epoch)
 
-	result.Version = trimmed[colon+1:]
+	result.Version = rest
 	if len(result.Version) == 0 {
 		return fmt.Errorf("nothing after colon in version number")
 	}
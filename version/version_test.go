@@ -383,4 +383,157 @@ func TestString(t *testing.T) {
 	}
 }
 
+func TestPreRelease(t *testing.T) {
+	for _, test := range []struct {
+		Version    Version
+		PreRelease bool
+		Tag        string
+	}{
+		{v(0, "1.0", "1"), false, ""},
+		{v(0, "1.0~rc1", "1"), true, "rc1"},
+		{v(0, "1.0~beta~2", ""), true, "2"},
+		{v(0, "1.0", "1~bpo1"), true, "bpo1"},
+		{v(1, "2.0~alpha", "0~exp1"), true, "exp1"},
+	} {
+		if got := test.Version.IsPreRelease(); got != test.PreRelease {
+			t.Errorf("IsPreRelease(%q) = %v, want %v", test.Version, got, test.PreRelease)
+		}
+		if got := test.Version.PreReleaseTag(); got != test.Tag {
+			t.Errorf("PreReleaseTag(%q) = %q, want %q", test.Version, got, test.Tag)
+		}
+	}
+}
+
+func TestUpstreamVersionAndDebianRevision(t *testing.T) {
+	for _, test := range []struct {
+		In       string
+		Upstream string
+		Revision string
+	}{
+		{"2.10-3", "2.10", "3"},
+		{"1:2.10-1ubuntu2+deb12u1", "2.10", "1ubuntu2+deb12u1"},
+		{"1.2-3-4", "1.2-3", "4"},
+		{"0.9.8", "0.9.8", ""},
+	} {
+		ver, err := Parse(test.In)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", test.In, err)
+		}
+		if got := ver.UpstreamVersion(); got != test.Upstream {
+			t.Errorf("UpstreamVersion(%q) = %q, want %q", test.In, got, test.Upstream)
+		}
+		if got := ver.DebianRevision(); got != test.Revision {
+			t.Errorf("DebianRevision(%q) = %q, want %q", test.In, got, test.Revision)
+		}
+	}
+}
+
+func TestCompatibleWith(t *testing.T) {
+	for _, test := range []struct {
+		A, B       string
+		Compatible bool
+	}{
+		{"1:2.10-3", "1:2.12-1", true},
+		{"2.10-3", "2.10-3", true},
+		{"2.10-3", "2~rc1-1", true},
+		{"2.10+dfsg-1", "2.9-1", true},
+		{"20240101-1", "20240101-2", true},
+		{"1:2.10-3", "1:3.0-1", false},
+		{"1:2.10-3", "2.10-3", false},
+		{"2.10-3", "20.1-1", false},
+		{"20240101-1", "20240102-1", false},
+	} {
+		a, err := Parse(test.A)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", test.A, err)
+		}
+		b, err := Parse(test.B)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", test.B, err)
+		}
+		if got := a.CompatibleWith(b); got != test.Compatible {
+			t.Errorf("%q.CompatibleWith(%q) = %v, want %v", test.A, test.B, got, test.Compatible)
+		}
+		if got := b.CompatibleWith(a); got != test.Compatible {
+			t.Errorf("%q.CompatibleWith(%q) = %v, want %v", test.B, test.A, got, test.Compatible)
+		}
+	}
+}
+
+func TestParseEpoch(t *testing.T) {
+	for _, test := range []struct {
+		In    string
+		Epoch int
+		Rest  string
+	}{
+		{"1:2.10-2", 1, "2.10-2"},
+		{"2.10-2", 0, "2.10-2"},
+		{"0:1.0", 0, "1.0"},
+		{"12:1:2.0", 12, "1:2.0"},
+	} {
+		epoch, rest, err := ParseEpoch(test.In)
+		if err != nil {
+			t.Errorf("ParseEpoch(%q) returned an error: %v", test.In, err)
+			continue
+		}
+		if epoch != test.Epoch || rest != test.Rest {
+			t.Errorf("ParseEpoch(%q) = %d, %q, want %d, %q", test.In, epoch, rest, test.Epoch, test.Rest)
+		}
+	}
+
+	for _, in := range []string{"a:1.0", "-1:1.0", ":1.0"} {
+		if _, _, err := ParseEpoch(in); err == nil {
+			t.Errorf("Expected an error, but %q was parsed without an error", in)
+		}
+	}
+}
+
+func TestExplainCompare(t *testing.T) {
+	result, explanation, err := ExplainCompare("1.0~beta", "0.9+final")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != 1 {
+		t.Errorf("ExplainCompare(1.0~beta, 0.9+final) = %d, want 1", result)
+	}
+	if want := `epoch: 0 == 0
+upstream version: "1.0~beta" vs "0.9+final"
+  non-digits: "" == ""
+  digits: 1 > 0
+1.0~beta > 0.9+final
+`; explanation != want {
+		t.Errorf("ExplainCompare explanation = %q, want %q", explanation, want)
+	}
+
+	pairs := [][2]string{
+		{"1.0~beta", "1.0"},
+		{"1.0", "1.0-0"},
+		{"1:0.1", "2.0"},
+		{"1.0-1", "1.0-1"},
+		{"1.0-1ubuntu1", "1.0-1"},
+		{"1.0a", "1.0+"},
+		{"1.001", "1.1"},
+		{"2.10-2", "2.9-12"},
+		{"1.0~~", "1.0~"},
+		{"0.0.0+git20230101", "0.0.0"},
+	}
+	for _, pair := range pairs {
+		for _, p := range [][2]string{pair, {pair[1], pair[0]}} {
+			result, _, err := ExplainCompare(p[0], p[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			a, _ := Parse(p[0])
+			b, _ := Parse(p[1])
+			if want := sign(Compare(a, b)); result != want {
+				t.Errorf("ExplainCompare(%q, %q) = %d, want %d", p[0], p[1], result, want)
+			}
+		}
+	}
+
+	if _, _, err := ExplainCompare("1.0", "a:1"); err == nil {
+		t.Errorf("Expected an error, but %q was parsed without an error", "a:1")
+	}
+}
+
 // vim:ts=4:sw=4:noexpandtab foldmethod=marker